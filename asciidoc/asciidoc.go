@@ -0,0 +1,286 @@
+// Package asciidoc renders parsed notes as AsciiDoc.
+//
+// Topics become sections, '==' for top level topics, '===' for the next
+// level, and so on. Skipped levels are closed up, see block.Normalise, since
+// AsciiDoc warns of them. Each section is given the slug of its title as its
+// ID. Lists become unordered and ordered lists. AsciiDoc has no equivalent
+// for some Daft Wullie phrases so the following conventions are used:
+//   - strong phrases become strong, '**strong**', key phrases become
+//     emphasis, '__emphasis__', and snippets become literal monospace
+//   - positive, negative, and artifact phrases become text with the roles
+//     'dw-positive', 'dw-negative', and 'dw-artifact', e.g.
+//     '[.dw-positive]##cheap##', the same classes as the html package uses
+//   - quotes are wrapped in curved double quotes, '"`quote`"'
+//   - wiki links become cross references to the AsciiDoc file named after
+//     the slug of the note, e.g. 'xref:other-note.adoc#topic[Other Note#Topic]'
+//   - links with unsafe schemes, see html.SafeURL, are rendered as text
+//
+// Text is escaped with numeric character references so AsciiDoc neither
+// formats it nor applies its typographic replacements.
+package asciidoc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+)
+
+// maxDepth is the deepest AsciiDoc allows lists to be nested.
+const maxDepth = 5
+
+// blockStart matches the start of a line that would be read as a list item,
+// admonition, or other block markup.
+var blockStart = regexp.MustCompile(`^(?:[.\-=/:'<>]|\w+[.)](?:\s|$)|[A-Z]+:\s)`)
+
+// Render renders 'notes' as AsciiDoc.
+func Render(notes ast.Notes) string {
+	sb := &strings.Builder{}
+
+	var prev block.Block
+	for _, b := range block.Normalise(block.Build(notes)) {
+		if _, ok := b.(block.Break); ok {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		if _, ok := prev.(*block.List); ok {
+			if _, ok := b.(*block.List); ok {
+				sb.WriteString("//-\n\n") // Keeps the lists apart
+			}
+		}
+		writeBlock(sb, b)
+		prev = b
+	}
+
+	return sb.String()
+}
+
+func writeBlock(sb *strings.Builder, b block.Block) {
+	switch v := b.(type) {
+	case block.Heading:
+		title := Inline(v.Nodes)
+		if id := ast.Slug(ast.MakeTextLine(v.Nodes...).Text()); id != "" {
+			sb.WriteString("[#" + id + "]\n")
+		}
+		sb.WriteString(strings.Repeat("=", headingLevel(v.Level)+1) + " " + title + "\n")
+
+	case block.Para:
+		sb.WriteString(escapeLineStart(Inline(v.Nodes)))
+		sb.WriteString("\n")
+
+	case *block.List:
+		writeList(sb, v, 1)
+	}
+}
+
+func writeList(sb *strings.Builder, l *block.List, depth int) {
+	if depth > maxDepth {
+		depth = maxDepth
+	}
+
+	marker := strings.Repeat("*", depth)
+	if l.Ordered {
+		marker = strings.Repeat(".", depth)
+	}
+
+	for i, item := range l.Items {
+		if l.Ordered && (i == 0 && item.Num != 1 || i > 0 && item.Start > 0) {
+			sb.WriteString("[start=" + strconv.Itoa(item.Num) + "]\n")
+		}
+
+		sb.WriteString(marker + " " + Inline(item.Nodes) + "\n")
+		for _, sub := range item.Lists {
+			writeList(sb, sub, depth+1)
+		}
+	}
+}
+
+// Inline renders a sequence of phrase nodes as inline AsciiDoc.
+func Inline(ns []ast.Node) string {
+	sb := &strings.Builder{}
+	for _, n := range ns {
+		writeNode(sb, n)
+	}
+	return sb.String()
+}
+
+func writeNode(sb *strings.Builder, n ast.Node) {
+
+	writeGroup := func(open string, n ast.Node, close string) {
+		sb.WriteString(open)
+		if p, ok := n.(ast.Parent); ok {
+			for _, c := range p.Nodes() {
+				writeNode(sb, c)
+			}
+		} else {
+			sb.WriteString(Escape(n.Text()))
+		}
+		sb.WriteString(close)
+	}
+
+	switch n.Type() {
+	case ast.KeyPhrase:
+		writeGroup("__", n, "__")
+	case ast.Positive:
+		writeGroup("[.dw-positive]##", n, "##")
+	case ast.Negative:
+		writeGroup("[.dw-negative]##", n, "##")
+	case ast.Strong:
+		writeGroup("**", n, "**")
+	case ast.Quote:
+		writeGroup("\"`", n, "`\"")
+	case ast.Artifact:
+		writeGroup("[.dw-artifact]##", n, "##")
+	case ast.Snippet:
+		writeSnippet(sb, n.Text())
+	case ast.Link:
+		writeLink(sb, n)
+	case ast.WikiLink:
+		writeWikiLink(sb, n)
+	case ast.Tag:
+		sb.WriteString(Escape("#" + n.Text()))
+	default:
+		writeGroup("", n, "")
+	}
+}
+
+// writeSnippet writes 's' as literal monospace using a passthrough or, if 's'
+// contains a '+' that would end the passthrough, as escaped monospace.
+func writeSnippet(sb *strings.Builder, s string) {
+	if s != "" && !strings.Contains(s, "+") {
+		sb.WriteString("``+" + s + "+``")
+		return
+	}
+	sb.WriteString("``" + Escape(s) + "``")
+}
+
+func writeLink(sb *strings.Builder, n ast.Node) {
+	l, ok := n.(ast.LinkNode)
+	switch {
+	case !ok, !html.SafeURL(l.URL):
+		sb.WriteString(Escape(n.Text()))
+	case l.Label == "":
+		sb.WriteString("link:" + escapeURL(l.URL) + "[]")
+	default:
+		sb.WriteString("link:" + escapeURL(l.URL) + "[" + Escape(l.Label) + "]")
+	}
+}
+
+// writeWikiLink writes a wiki link as a cross reference to the AsciiDoc file
+// named after the slug of the note.
+func writeWikiLink(sb *strings.Builder, n ast.Node) {
+	l, ok := n.(ast.WikiLinkNode)
+	if !ok {
+		sb.WriteString(Escape(n.Text()))
+		return
+	}
+
+	target := ast.Slug(l.Note) + ".adoc"
+	if l.Topic != "" {
+		target += "#" + ast.Slug(l.Topic)
+	}
+	sb.WriteString("xref:" + target + "[" + Escape(l.Text()) + "]")
+}
+
+// Escape escapes characters within 's' that AsciiDoc would otherwise read as
+// markup, attribute references, or macros, or replace with typographic
+// characters, e.g. '--' with an em dash. They are replaced with numeric
+// character references.
+func Escape(s string) string {
+	ru := []rune(s)
+	sb := strings.Builder{}
+
+	at := func(i int) rune {
+		if i < 0 || i >= len(ru) {
+			return ' '
+		}
+		return ru[i]
+	}
+
+	for i, v := range ru {
+		prev, next := at(i-1), at(i+1)
+
+		escape := false
+		switch {
+		case strings.ContainsRune("*_`#^~+[]{}\\|<>&", v):
+			escape = true
+		case v == '-' && prev == '-', v == '.' && prev == '.':
+			escape = true // Dashes and ellipses
+		case v == ':' && (next == ':' || isScheme(ru[:i])):
+			escape = true // Description lists and URLs
+		case v == ';' && next == ';':
+			escape = true // Description lists
+		case v == '\'' && isWordRune(prev) && isWordRune(next):
+			escape = true // Apostrophes
+		case v == '(' && hasPrefix(ru[i+1:], "C)", "R)", "TM)"):
+			escape = true // Symbols
+		}
+
+		if escape {
+			sb.WriteString("&#" + strconv.Itoa(int(v)) + ";")
+		} else {
+			sb.WriteRune(v)
+		}
+	}
+
+	return sb.String()
+}
+
+// escapeLineStart escapes the start of a paragraph that would otherwise be
+// read as block markup, e.g. a list item, block title, or admonition.
+func escapeLineStart(s string) string {
+	if blockStart.MatchString(s) {
+		return "{empty}" + s
+	}
+	return s
+}
+
+// escapeURL percent encodes characters that would end the target of a link.
+func escapeURL(s string) string {
+	return strings.NewReplacer(" ", "%20", "[", "%5B", "]", "%5D").Replace(s)
+}
+
+// isScheme returns true if 'ru' ends with a URL scheme that AsciiDoc
+// recognises in text.
+func isScheme(ru []rune) bool {
+	s := string(ru)
+	for _, scheme := range []string{"http", "https", "ftp", "irc", "mailto", "file"} {
+		if strings.HasSuffix(s, scheme) {
+			rest := []rune(s[:len(s)-len(scheme)])
+			if len(rest) == 0 || !isWordRune(rest[len(rest)-1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasPrefix(ru []rune, prefixes ...string) bool {
+	s := string(ru)
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordRune(ru rune) bool {
+	return unicode.IsLetter(ru) || unicode.IsDigit(ru)
+}
+
+func headingLevel(level int) int {
+	switch {
+	case level < 1:
+		return 1
+	case level > 5:
+		return 5
+	}
+	return level
+}
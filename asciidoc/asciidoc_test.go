@@ -0,0 +1,65 @@
+package asciidoc
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_1(t *testing.T) {
+
+	in := `# Cheese & Wine
+### Types
+. Chedder from $Somerset
+.. Mild *and* mature, see ` + "`man cheese`" + `
+. Brie
+-Smelly- but **ripe** and +tasty with **key**+ [[Wine#Reds]]
+See [shop](https://a.org/c) not [this](javascript:x)
+Con*cat*enated "quote" ` + "`a+b`" + `
+\. not a list
+NOTE: not an admonition
+!3 Curdling
+! Ripening
+. Unordered`
+
+	exp := `[#cheese-wine]
+== Cheese &#38; Wine
+
+[#types]
+=== Types
+
+* Chedder from [.dw-artifact]##Somerset##
+** Mild **and** mature, see ` + "``+man cheese+``" + `
+* Brie
+
+[.dw-negative]##Smelly## but __ripe__ and [.dw-positive]##tasty with __key__## xref:wine.adoc#reds[Wine&#35;Reds]
+
+See link:https://a.org/c[shop] not this
+
+Con**cat**enated "` + "`quote`" + `" ` + "``a&#43;b``" + `
+
+{empty}. not a list
+
+{empty}NOTE: not an admonition
+
+[start=3]
+. Curdling
+. Ripening
+
+//-
+
+* Unordered
+`
+
+	act := Render(parser.ParseAll(scanner.ScanAll(in)))
+	require.Equal(t, exp, act)
+}
+
+func TestEscape_1(t *testing.T) {
+	require.Equal(t, "a&#95;b &#42;c&#42; &#96;d&#96; &#35;e", Escape("a_b *c* `d` #e"))
+	require.Equal(t, "a-&#45;b .&#46;&#46; don&#39;t &#40;C) x&#58;: https&#58;//x", Escape("a--b ... don't (C) x:: https://x"))
+	require.Equal(t, "&#123;name&#125; &#91;x&#93; &#60;&#60;y&#62;&#62; a &#38; b", Escape("{name} [x] <<y>> a & b"))
+}
@@ -0,0 +1,44 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFmtString_RoundTrip(t *testing.T) {
+
+	parse := func(s string) ast.Notes {
+		return parser.ParseAll(scanner.ScanAll(s))
+	}
+
+	in := `# Topic with *raw* \text
+## Sub topic
+. Point with 2 \* 3 and \-escaped\-
+\. Not a point
+  \# Not a topic
+\!5 Not a numbered point
+!5 \5 apples
+! 5 apples
+!\5 apples
+!0 zero items
+!99999999999999999999 big
+. .dot and .\.dots
+!! -Negative with "quote" $artifact
+Text with \$dollars, \#not-a-tag, \[[not a link]] and \https://not.a.link
+` + "Snippet `a\\`b\\\\c` and a **key phrase** #tag [[Note#Topic]]" + `
+[label](https://example.com/a_(b)) and https://example.com`
+
+	exp := parse(in)
+	act := parse(strings.TrimSuffix(ast.FmtString(exp), "\n"))
+	require.Equal(t, exp, act, ast.FmtString(exp))
+
+	require.Equal(t, "! 5 apples\n", ast.FmtString(parse("! 5 apples")))
+	require.Equal(t, "!\\5 apples\n", ast.FmtString(parse("!\\5 apples")))
+	require.Equal(t, "!\\0 zero items\n", ast.FmtString(parse("!0 zero items")))
+}
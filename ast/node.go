@@ -26,26 +26,108 @@ type (
 		NodeType
 		Children []Node
 	}
+
+	// HeadingNode is a topic line nested to a specific level. Level one is a
+	// Topic and all deeper levels are SubTopics.
+	HeadingNode struct {
+		Level    int
+		Children []Node
+	}
+
+	// ListItemNode is an ordered or unordered list item nested to a specific
+	// depth. Depth one is a BulPoint or NumPoint and all deeper depths are
+	// SubBulPoints or SubNumPoints.
+	//
+	// Start is the explicit number an ordered item was given, e.g. '!5', or
+	// zero if it wasn't given one. Num is the resolved number of an ordered
+	// item, zero until resolved, see Number.
+	ListItemNode struct {
+		Depth    int
+		Ordered  bool
+		Start    int
+		Num      int
+		Children []Node
+	}
+
+	// LinkNode is a hyperlink, either a bare URL or a URL with a label.
+	LinkNode struct {
+		URL   string
+		Label string
+	}
+
+	// WikiLinkNode is a link to another note and, optionally, a topic within
+	// it, e.g. '[[Other Note#Topic]]'.
+	WikiLinkNode struct {
+		Note  string
+		Topic string
+	}
 )
 
-func (n TextNode) Type() NodeType   { return n.NodeType }
-func (n ParentNode) Type() NodeType { return n.NodeType }
+func (n TextNode) Type() NodeType     { return n.NodeType }
+func (n LinkNode) Type() NodeType     { return Link }
+func (n WikiLinkNode) Type() NodeType { return WikiLink }
+func (n ParentNode) Type() NodeType   { return n.NodeType }
 
-func (n TextNode) Text() string { return n.Txt }
-func (n ParentNode) Text() string {
-	sb := strings.Builder{}
-	for _, c := range n.Children {
-		sb.WriteString(c.Text())
+func (n HeadingNode) Type() NodeType {
+	if n.Level > 1 {
+		return SubTopic
 	}
-	return sb.String()
+	return Topic
+}
+
+func (n ListItemNode) Type() NodeType {
+	switch {
+	case n.Ordered && n.Depth > 1:
+		return SubNumPoint
+	case n.Ordered:
+		return NumPoint
+	case n.Depth > 1:
+		return SubBulPoint
+	default:
+		return BulPoint
+	}
+}
+
+func (n TextNode) Text() string     { return n.Txt }
+func (n ParentNode) Text() string   { return childText(n.Children) }
+func (n HeadingNode) Text() string  { return childText(n.Children) }
+func (n ListItemNode) Text() string { return childText(n.Children) }
+
+func (n LinkNode) Text() string {
+	if n.Label != "" {
+		return n.Label
+	}
+	return n.URL
+}
+
+func (n WikiLinkNode) Text() string {
+	if n.Topic != "" {
+		return n.Note + "#" + n.Topic
+	}
+	return n.Note
 }
 
-func (n ParentNode) Nodes() []Node { return n.Children }
+func (n ParentNode) Nodes() []Node   { return n.Children }
+func (n HeadingNode) Nodes() []Node  { return n.Children }
+func (n ListItemNode) Nodes() []Node { return n.Children }
 
 func MakeEmptyLine() TextNode       { return makeTextNode(EmptyLine, "") }
 func MakeText(s string) TextNode    { return makeTextNode(Text, s) }
 func MakeSnippet(s string) TextNode { return makeTextNode(Snippet, s) }
+func MakeTag(name string) TextNode  { return makeTextNode(Tag, name) }
 
+// MakeLink makes a hyperlink to 'url' with an optional 'label'.
+func MakeLink(url, label string) LinkNode { return LinkNode{URL: url, Label: label} }
+
+// MakeWikiLink makes a link to the 'note' and, optionally, a 'topic' within
+// it.
+func MakeWikiLink(note, topic string) WikiLinkNode {
+	return WikiLinkNode{Note: note, Topic: topic}
+}
+
+// The topic and list item constructors make ParentNodes, as they did before
+// lines could be nested to any depth, see MakeHeading and MakeListItem for
+// the nested forms. Use AsHeading and AsListItem to handle both.
 func MakeTopic(ns ...Node) ParentNode       { return makeParentNode(Topic, ns) }
 func MakeSubTopic(ns ...Node) ParentNode    { return makeParentNode(SubTopic, ns) }
 func MakeBulPoint(ns ...Node) ParentNode    { return makeParentNode(BulPoint, ns) }
@@ -54,6 +136,52 @@ func MakeNumPoint(ns ...Node) ParentNode    { return makeParentNode(NumPoint, ns
 func MakeSubNumPoint(ns ...Node) ParentNode { return makeParentNode(SubNumPoint, ns) }
 func MakeTextLine(ns ...Node) ParentNode    { return makeParentNode(TextLine, ns) }
 
+// MakeHeading makes a topic line nested to 'level', level one being a top
+// level Topic.
+func MakeHeading(level int, ns ...Node) HeadingNode {
+	return HeadingNode{Level: level, Children: makeChildren(ns)}
+}
+
+// MakeListItem makes an ordered or unordered list item nested to 'depth',
+// depth one being a top level point. Depths less than one are made one.
+func MakeListItem(depth int, ordered bool, ns ...Node) ListItemNode {
+	if depth < 1 {
+		depth = 1
+	}
+	return ListItemNode{Depth: depth, Ordered: ordered, Children: makeChildren(ns)}
+}
+
+// AsHeading returns 'n' as a HeadingNode if it is a topic, converting the
+// ParentNodes made by MakeTopic and MakeSubTopic.
+func AsHeading(n Node) (HeadingNode, bool) {
+	switch v := n.(type) {
+	case HeadingNode:
+		return v, true
+	case ParentNode:
+		if v.NodeType == Topic || v.NodeType == SubTopic {
+			return HeadingNode{Level: Level(v), Children: v.Children}, true
+		}
+	}
+	return HeadingNode{}, false
+}
+
+// AsListItem returns 'n' as a ListItemNode if it is a list item, converting
+// the ParentNodes made by MakeBulPoint, MakeNumPoint, etc.
+func AsListItem(n Node) (ListItemNode, bool) {
+	switch v := n.(type) {
+	case ListItemNode:
+		return v, true
+	case ParentNode:
+		switch v.NodeType {
+		case BulPoint, SubBulPoint:
+			return ListItemNode{Depth: Level(v), Children: v.Children}, true
+		case NumPoint, SubNumPoint:
+			return ListItemNode{Depth: Level(v), Ordered: true, Children: v.Children}, true
+		}
+	}
+	return ListItemNode{}, false
+}
+
 func MakeKeyPhrase(ns ...Node) ParentNode { return makeParentNode(KeyPhrase, ns) }
 func MakePositive(ns ...Node) ParentNode  { return makeParentNode(Positive, ns) }
 func MakeNegative(ns ...Node) ParentNode  { return makeParentNode(Negative, ns) }
@@ -66,8 +194,20 @@ func makeTextNode(nt NodeType, s string) TextNode {
 }
 
 func makeParentNode(nt NodeType, ns []Node) ParentNode {
+	return ParentNode{NodeType: nt, Children: makeChildren(ns)}
+}
+
+func makeChildren(ns []Node) []Node {
 	if ns == nil {
-		return ParentNode{NodeType: nt, Children: []Node{}}
+		return []Node{}
 	}
-	return ParentNode{NodeType: nt, Children: ns}
+	return ns
+}
+
+func childText(ns []Node) string {
+	sb := strings.Builder{}
+	for _, c := range ns {
+		sb.WriteString(c.Text())
+	}
+	return sb.String()
 }
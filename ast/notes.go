@@ -1,7 +1,9 @@
 package ast
 
 import (
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 type (
@@ -30,6 +32,75 @@ func descendNode(n Node, lineNum, depth, orderIdx int, f DescendFunc) {
 	}
 }
 
+// Level returns the nesting level of a heading or the depth of a list item,
+// at least one for list items.
+// Nodes of the old Topic, SubTopic, BulPoint, etc types that are not
+// HeadingNodes or ListItemNodes are considered to be nested to level one or
+// two. Zero is returned for all other nodes.
+func Level(n Node) int {
+	switch v := n.(type) {
+	case HeadingNode:
+		return v.Level
+	case ListItemNode:
+		if v.Depth < 1 {
+			return 1
+		}
+		return v.Depth
+	}
+
+	switch n.Type() {
+	case Topic, BulPoint, NumPoint:
+		return 1
+	case SubTopic, SubBulPoint, SubNumPoint:
+		return 2
+	}
+	return 0
+}
+
+// Slug returns the anchor form of a topic title or note name, i.e. lower
+// case words joined by '-' with all other punctuation removed. Slugs are used
+// to match and link to topics regardless of case and spacing.
+func Slug(s string) string {
+	sb := strings.Builder{}
+	gap := false
+
+	for _, ru := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(ru), unicode.IsDigit(ru):
+			if gap && sb.Len() > 0 {
+				sb.WriteRune('-')
+			}
+			sb.WriteRune(ru)
+			gap = false
+		case unicode.IsSpace(ru), ru == '-', ru == '_':
+			gap = true
+		}
+	}
+
+	return sb.String()
+}
+
+// Anchors makes unique anchors for the topics of a note, in order of
+// appearance. The first topic with a given slug, see Slug, is anchored by it,
+// later ones by the slug suffixed with '-1', '-2', etc. Topics without a slug
+// are anchored as 'topic'.
+type Anchors map[string]bool
+
+// Next returns the anchor of the next topic, titled 'title'.
+func (a Anchors) Next(title string) string {
+	base := Slug(title)
+	if base == "" {
+		base = "topic"
+	}
+
+	id := base
+	for i := 1; a[id]; i++ {
+		id = base + "-" + strconv.Itoa(i)
+	}
+	a[id] = true
+	return id
+}
+
 func RemoveExtraLines(notes Notes) Notes {
 
 	r := []Node{}
@@ -47,9 +118,62 @@ func RemoveExtraLines(notes Notes) Notes {
 	return Notes(r)
 }
 
+// Number returns a copy of 'notes' with the number of each ordered list item
+// resolved. Numbering follows these rules:
+//   - the first item of a sequence is numbered one
+//   - each following item at the same depth is numbered one more than the last
+//   - an item with an explicit start, e.g. '!5', is numbered as such and the
+//     items following it continue from there
+//   - deeper items, ordered or not, do not interrupt a sequence
+//   - a shallower list item ends all deeper sequences
+//   - an unordered item ends the sequence at its own depth
+//   - any other line, i.e. a topic, text, or empty line, ends all sequences
+//
+// Items with a depth less than one are numbered as if at depth one.
+func Number(notes Notes) Notes {
+
+	r := make([]Node, len(notes))
+	counts := []int{} // Last number used at each depth
+
+	for i, n := range notes {
+		li, ok := AsListItem(n)
+		if !ok {
+			counts = counts[:0]
+			r[i] = n
+			continue
+		}
+
+		depth := Level(li)
+		for len(counts) < depth {
+			counts = append(counts, 0)
+		}
+		counts = counts[:depth]
+
+		switch {
+		case !li.Ordered:
+			counts[depth-1] = 0
+		case li.Start > 0:
+			counts[depth-1] = li.Start
+		default:
+			counts[depth-1]++
+		}
+
+		if li.Ordered {
+			li.Num = counts[depth-1]
+		}
+		r[i] = li
+	}
+
+	return Notes(r)
+}
+
 func PlainString(notes Notes) string {
 	sb := strings.Builder{}
-	for _, n := range notes {
+	for _, n := range Number(notes) {
+		if li, ok := n.(ListItemNode); ok && li.Ordered {
+			sb.WriteString(strconv.Itoa(li.Num))
+			sb.WriteString(". ")
+		}
 		s := strings.TrimSpace(n.Text())
 		sb.WriteString(s)
 		sb.WriteString("\n")
@@ -57,51 +181,108 @@ func PlainString(notes Notes) string {
 	return sb.String()
 }
 
+// FmtString returns the formatted string of 'notes', i.e. Daft Wullie text
+// that parses back into the same notes. Text is escaped where needed but all
+// phrases are closed, e.g. '-negative' becomes '-negative-'.
 func FmtString(notes Notes) string {
 	sb := &strings.Builder{}
 	for _, n := range notes {
-		fmtNodeString(sb, n)
+		fmtNodeString(sb, n, false)
 		sb.WriteString("\n")
 	}
 	return sb.String()
 }
 
-func fmtNodeString(sb *strings.Builder, n Node) {
+// FmtNode returns the formatted string of a single node, e.g. a phrase node,
+// without a trailing line feed.
+func FmtNode(n Node) string {
+	sb := &strings.Builder{}
+	fmtNodeString(sb, n, false)
+	return sb.String()
+}
+
+// FmtChildren returns the formatted string of the children of 'n' if it is a
+// parent else the text of 'n'. For a phrase this recovers the text between
+// its delimiters, e.g. the quote '"a *b*"' gives 'a *b*'.
+func FmtChildren(n Node) string {
+	p, ok := n.(Parent)
+	if !ok {
+		return n.Text()
+	}
+
+	sb := &strings.Builder{}
+	for _, c := range p.Nodes() {
+		fmtNodeString(sb, c, false)
+	}
+	return sb.String()
+}
+
+// fmtNodeString writes the formatted string of 'n'. If 'raw' is true, text is
+// not escaped, as is the case within topics.
+func fmtNodeString(sb *strings.Builder, n Node, raw bool) {
 
 	writeGroup := func(prefix string, n Node, suffix string) {
 		sb.WriteString(prefix)
 
-		if p, ok := n.(ParentNode); ok {
+		if p, ok := n.(Parent); ok {
 			for _, sub := range p.Nodes() {
-				fmtNodeString(sb, sub)
+				fmtNodeString(sb, sub, raw)
 			}
+		} else if raw {
+			sb.WriteString(n.Text())
 		} else {
-			s := n.Text()
-			sb.WriteString(s)
+			sb.WriteString(EscapeText(n.Text()))
 		}
 
 		sb.WriteString(suffix)
 	}
 
+	// writeLine writes a line node, escaping the start of its content if it
+	// would otherwise be read as part of the line symbol. Leading whitespace
+	// is skipped over when the line has no symbol, i.e. is a text line,
+	// otherwise only content directly after the symbol is escaped.
+	writeLine := func(prefix string, n Node, lead string) {
+		sub := &strings.Builder{}
+		for _, c := range children(n) {
+			fmtNodeString(sub, c, raw)
+		}
+
+		s := sub.String()
+		trimmed := s
+		if prefix == "" {
+			trimmed = strings.TrimLeftFunc(s, unicode.IsSpace)
+		}
+		if trimmed != "" && strings.ContainsRune(lead, []rune(trimmed)[0]) {
+			i := len(s) - len(trimmed)
+			s = s[:i] + "\\" + s[i:]
+		}
+
+		sb.WriteString(prefix)
+		sb.WriteString(s)
+	}
+
 	switch n.Type() {
 	case Text:
 		writeGroup("", n, "")
 
-	case Topic:
-		writeGroup("#", n, "")
-	case SubTopic:
-		writeGroup("##", n, "")
-
-	case BulPoint:
-		writeGroup(".", n, "")
-	case SubBulPoint:
-		writeGroup("..", n, "")
-	case NumPoint:
-		writeGroup("!", n, "")
-	case SubNumPoint:
-		writeGroup("!!", n, "")
-
-	case TextLine, EmptyLine:
+	case Topic, SubTopic:
+		sb.WriteString(strings.Repeat("#", Level(n)))
+		for _, c := range children(n) {
+			fmtNodeString(sb, c, true)
+		}
+
+	case BulPoint, SubBulPoint:
+		writeLine(strings.Repeat(".", Level(n)), n, ".")
+	case NumPoint, SubNumPoint:
+		prefix := strings.Repeat("!", Level(n))
+		if li, ok := n.(ListItemNode); ok && li.Start > 0 {
+			prefix += strconv.Itoa(li.Start)
+		}
+		writeLine(prefix, n, "!0123456789")
+
+	case TextLine:
+		writeLine("", n, "#.!")
+	case EmptyLine:
 		writeGroup("", n, "")
 
 	case KeyPhrase:
@@ -115,6 +296,74 @@ func fmtNodeString(sb *strings.Builder, n Node) {
 	case Quote:
 		writeGroup(`"`, n, `"`)
 	case Snippet:
-		writeGroup("`", n, "`")
+		sb.WriteString("`")
+		if raw {
+			sb.WriteString(n.Text())
+		} else {
+			sb.WriteString(snippetEscaper.Replace(n.Text()))
+		}
+		sb.WriteString("`")
+	case Artifact:
+		writeGroup("$", n, "$")
+	case Tag:
+		sb.WriteString("#" + n.Text())
+	case WikiLink:
+		sb.WriteString("[[" + n.Text() + "]]")
+	case Link:
+		if l, ok := n.(LinkNode); ok && l.Label != "" {
+			sb.WriteString("[" + l.Label + "](" + l.URL + ")")
+		} else {
+			sb.WriteString(n.Text())
+		}
+	}
+}
+
+var snippetEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+
+// EscapeText escapes the symbols within the text 's' so it scans as text when
+// placed within a line, i.e. not at the start of one.
+func EscapeText(s string) string {
+	sb := strings.Builder{}
+	ru := []rune(s)
+
+	for i, v := range ru {
+		escape := false
+
+		switch {
+		case strings.ContainsRune("\\+-*\"$`[", v):
+			escape = true
+		case v == '#' && i+1 < len(ru):
+			next := ru[i+1]
+			escape = unicode.IsLetter(next) || unicode.IsDigit(next) || next == '_'
+		case i == 0 || !unicode.IsLetter(ru[i-1]) && !unicode.IsDigit(ru[i-1]):
+			for _, scheme := range []string{"https://", "http://", "ftp://", "mailto:"} {
+				escape = escape || hasPrefixAt(ru, i, scheme)
+			}
+		}
+
+		if escape {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(v)
+	}
+
+	return sb.String()
+}
+
+// hasPrefixAt returns true if 'prefix' starts at index 'i' of 'ru'.
+func hasPrefixAt(ru []rune, i int, prefix string) bool {
+	for _, v := range prefix {
+		if i >= len(ru) || ru[i] != v {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+func children(n Node) []Node {
+	if p, ok := n.(Parent); ok {
+		return p.Nodes()
 	}
+	return []Node{}
 }
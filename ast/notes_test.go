@@ -0,0 +1,135 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func numbered(depth, num int, s string) ListItemNode {
+	n := MakeListItem(depth, true, MakeText(s))
+	n.Num = num
+	return n
+}
+
+func TestNumber_1(t *testing.T) {
+
+	in := Notes{
+		MakeHeading(1, MakeText("Process")),
+		MakeListItem(1, true, MakeText("a")),
+		MakeListItem(2, true, MakeText("a.a")),
+		MakeListItem(2, true, MakeText("a.b")),
+		MakeListItem(1, true, MakeText("b")),
+		MakeListItem(2, true, MakeText("b.a")),
+		MakeListItem(2, false, MakeText("b.-")),
+		MakeListItem(2, true, MakeText("b.b")),
+		MakeEmptyLine(),
+		MakeListItem(1, true, MakeText("c")),
+	}
+
+	exp := Notes{
+		MakeHeading(1, MakeText("Process")),
+		numbered(1, 1, "a"),
+		numbered(2, 1, "a.a"),
+		numbered(2, 2, "a.b"),
+		numbered(1, 2, "b"),
+		numbered(2, 1, "b.a"),
+		MakeListItem(2, false, MakeText("b.-")),
+		numbered(2, 1, "b.b"),
+		MakeEmptyLine(),
+		numbered(1, 1, "c"),
+	}
+
+	act := Number(in)
+	require.Equal(t, exp, act)
+}
+
+func TestNumber_2(t *testing.T) {
+
+	start := MakeListItem(1, true, MakeText("e"))
+	start.Start = 5
+
+	in := Notes{
+		MakeListItem(1, true, MakeText("a")),
+		start,
+		MakeListItem(1, true, MakeText("f")),
+		MakeListItem(1, false, MakeText("-")),
+		MakeListItem(1, true, MakeText("a")),
+		MakeTextLine(MakeText("text")),
+		MakeListItem(1, true, MakeText("a")),
+	}
+
+	exp := "1. a\n5. e\n6. f\n-\n1. a\ntext\n1. a\n"
+
+	act := PlainString(in)
+	require.Equal(t, exp, act)
+}
+
+func TestNumber_3(t *testing.T) {
+
+	// Lines made with the old, un-nested, constructors are numbered too
+	var topic ParentNode = MakeTopic(MakeText("Process"))
+	in := Notes{
+		topic,
+		MakeNumPoint(MakeText("a")),
+		MakeSubNumPoint(MakeText("a.a")),
+		MakeNumPoint(MakeText("b")),
+	}
+
+	exp := Notes{
+		topic,
+		numbered(1, 1, "a"),
+		numbered(2, 1, "a.a"),
+		numbered(1, 2, "b"),
+	}
+
+	require.Equal(t, exp, Number(in))
+	require.Equal(t, "#Process\n!a\n!!a.a\n!b\n", FmtString(in))
+}
+
+func TestNumber_4(t *testing.T) {
+
+	require.Equal(t, 1, MakeListItem(0, true).Depth)
+
+	// Depths less than one are numbered as depth one rather than panicking
+	in := Notes{
+		ListItemNode{Depth: 0, Ordered: true},
+		ListItemNode{Depth: -1, Ordered: true},
+		MakeListItem(1, true),
+	}
+	act := Number(in)
+
+	for i, n := range act {
+		require.Equal(t, i+1, n.(ListItemNode).Num)
+	}
+	require.Equal(t, "!\n!\n!\n", FmtString(in))
+}
+
+func TestFmtString_1(t *testing.T) {
+
+	start := MakeListItem(3, true, MakeText(" e"))
+	start.Start = 5
+
+	in := Notes{
+		MakeHeading(3, MakeText(" Topic")),
+		MakeListItem(3, false, MakeText(" point")),
+		start,
+	}
+
+	exp := "### Topic\n... point\n!!!5 e\n"
+
+	act := FmtString(in)
+	require.Equal(t, exp, act)
+}
+
+func TestFmtString_2(t *testing.T) {
+
+	in := Notes{
+		MakeTextLine(MakeArtifact(MakeText("Alice")), MakeText(" said "), MakeArtifact()),
+	}
+
+	exp := "$Alice$ said $$\n"
+
+	act := FmtString(in)
+	require.Equal(t, exp, act)
+}
@@ -22,6 +22,9 @@ const (
 	Quote                     = "Quote"
 	Artifact                  = "Artifact"
 	Snippet                   = "Snippet"
+	Link                      = "Link"
+	Tag                       = "Tag"
+	WikiLink                  = "WikiLink"
 )
 
 func (nt NodeType) IsLineNode() bool {
@@ -0,0 +1,204 @@
+// Package block groups the lines of parsed notes into blocks, i.e. headings,
+// paragraphs, and nested lists, so renderers of structured formats present
+// notes in the same way.
+package block
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+type (
+	// Block is a heading, paragraph, list, or break.
+	Block interface {
+		block()
+	}
+
+	// Heading is a topic line.
+	Heading struct {
+		Line  int
+		Level int
+		Nodes []ast.Node
+	}
+
+	// Para is a single line of text.
+	Para struct {
+		Line  int
+		Nodes []ast.Node
+	}
+
+	// Break is one or more consecutive empty lines.
+	Break struct {
+		Line int
+	}
+
+	// List is a series of list items of the same depth and kind.
+	List struct {
+		Line    int
+		Depth   int
+		Ordered bool
+		Items   []*Item
+	}
+
+	// Item is a single list item along with any lists nested within it. Num is
+	// the resolved number of ordered items, see ast.Number, and Start is the
+	// explicit number it was given, if any.
+	Item struct {
+		Line  int
+		Num   int
+		Start int
+		Nodes []ast.Node
+		Lists []*List
+	}
+)
+
+func (Heading) block() {}
+func (Para) block()    {}
+func (Break) block()   {}
+func (*List) block()   {}
+
+// Build groups 'notes' into blocks. Line numbers start from one. Leading and
+// trailing whitespace is trimmed from the content of each line.
+func Build(notes ast.Notes) []Block {
+
+	r := []Block{}
+	lists := []*List{} // Open lists, deepest last
+
+	for i, n := range ast.Number(notes) {
+		line := i + 1
+
+		if li, ok := n.(ast.ListItemNode); ok {
+			if l := addItem(&lists, li, line); l != nil {
+				r = append(r, l)
+			}
+			continue
+		}
+
+		lists = lists[:0]
+
+		switch n.Type() {
+		case ast.EmptyLine:
+			if len(r) == 0 {
+				r = append(r, Break{Line: line})
+			} else if _, ok := r[len(r)-1].(Break); !ok {
+				r = append(r, Break{Line: line})
+			}
+
+		case ast.Topic, ast.SubTopic:
+			r = append(r, Heading{
+				Line:  line,
+				Level: ast.Level(n),
+				Nodes: Trim(children(n)),
+			})
+
+		default:
+			r = append(r, Para{Line: line, Nodes: Trim(children(n))})
+		}
+	}
+
+	return r
+}
+
+// Normalise returns a copy of 'bs' with the levels of headings renumbered
+// so none are skipped, e.g. a level three heading directly under a level one
+// heading becomes level two. Formats such as reStructuredText and AsciiDoc
+// reject documents that skip section levels.
+func Normalise(bs []Block) []Block {
+
+	r := make([]Block, len(bs))
+	open := []int{} // Levels of the open headings, deepest last
+
+	for i, b := range bs {
+		h, ok := b.(Heading)
+		if !ok {
+			r[i] = b
+			continue
+		}
+
+		for len(open) > 0 && open[len(open)-1] >= h.Level {
+			open = open[:len(open)-1]
+		}
+		open = append(open, h.Level)
+
+		h.Level = len(open)
+		r[i] = h
+	}
+
+	return r
+}
+
+// addItem adds the list item 'li' to the open 'lists', opening new lists as
+// needed. The new list is returned if it is a top level list.
+func addItem(lists *[]*List, li ast.ListItemNode, line int) *List {
+
+	open := *lists
+	for len(open) > 0 && open[len(open)-1].Depth > li.Depth {
+		open = open[:len(open)-1]
+	}
+
+	if len(open) > 0 {
+		last := open[len(open)-1]
+		if last.Depth == li.Depth && last.Ordered != li.Ordered {
+			open = open[:len(open)-1]
+		}
+	}
+
+	var newTop *List
+	if len(open) == 0 || open[len(open)-1].Depth < li.Depth {
+		l := &List{Line: line, Depth: li.Depth, Ordered: li.Ordered}
+
+		if len(open) == 0 {
+			newTop = l
+		} else {
+			parent := open[len(open)-1]
+			item := parent.Items[len(parent.Items)-1]
+			item.Lists = append(item.Lists, l)
+		}
+
+		open = append(open, l)
+	}
+
+	l := open[len(open)-1]
+	l.Items = append(l.Items, &Item{
+		Line:  line,
+		Num:   li.Num,
+		Start: li.Start,
+		Nodes: Trim(li.Children),
+	})
+
+	*lists = open
+	return newTop
+}
+
+// Trim returns a copy of 'ns' with leading whitespace trimmed from the first
+// node and trailing whitespace trimmed from the last, if they are text.
+func Trim(ns []ast.Node) []ast.Node {
+	if len(ns) == 0 {
+		return ns
+	}
+
+	r := make([]ast.Node, len(ns))
+	copy(r, ns)
+
+	if t, ok := r[0].(ast.TextNode); ok && t.Type() == ast.Text {
+		t.Txt = strings.TrimLeftFunc(t.Txt, unicode.IsSpace)
+		r[0] = t
+	}
+
+	last := len(r) - 1
+	if t, ok := r[last].(ast.TextNode); ok && t.Type() == ast.Text {
+		t.Txt = strings.TrimRightFunc(t.Txt, unicode.IsSpace)
+		r[last] = t
+	}
+
+	return r
+}
+
+func children(n ast.Node) []ast.Node {
+	if p, ok := n.(ast.Parent); ok {
+		return p.Nodes()
+	}
+	return []ast.Node{n}
+}
@@ -0,0 +1,68 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild_1(t *testing.T) {
+
+	in := ast.Notes{
+		ast.MakeHeading(1, ast.MakeText(" Process ")),
+		ast.MakeEmptyLine(),
+		ast.MakeEmptyLine(),
+		ast.MakeListItem(1, true, ast.MakeText(" a")),
+		ast.MakeListItem(2, false, ast.MakeText(" a.a")),
+		ast.MakeListItem(1, true, ast.MakeText(" b")),
+		ast.MakeListItem(1, false, ast.MakeText(" c")),
+		ast.MakeTextLine(ast.MakeText("Text ")),
+	}
+
+	exp := []Block{
+		Heading{Line: 1, Level: 1, Nodes: []ast.Node{ast.MakeText("Process")}},
+		Break{Line: 2},
+		&List{Line: 4, Depth: 1, Ordered: true, Items: []*Item{
+			&Item{Line: 4, Num: 1, Nodes: []ast.Node{ast.MakeText("a")}, Lists: []*List{
+				&List{Line: 5, Depth: 2, Items: []*Item{
+					&Item{Line: 5, Nodes: []ast.Node{ast.MakeText("a.a")}},
+				}},
+			}},
+			&Item{Line: 6, Num: 2, Nodes: []ast.Node{ast.MakeText("b")}},
+		}},
+		&List{Line: 7, Depth: 1, Items: []*Item{
+			&Item{Line: 7, Nodes: []ast.Node{ast.MakeText("c")}},
+		}},
+		Para{Line: 8, Nodes: []ast.Node{ast.MakeText("Text")}},
+	}
+
+	act := Build(in)
+	require.Equal(t, exp, act)
+}
+
+func TestNormalise_1(t *testing.T) {
+
+	in := []Block{
+		Heading{Level: 1},
+		Heading{Level: 3},
+		Para{},
+		Heading{Level: 4},
+		Heading{Level: 3},
+		Heading{Level: 2},
+		Heading{Level: 5},
+	}
+
+	exp := []Block{
+		Heading{Level: 1},
+		Heading{Level: 2},
+		Para{},
+		Heading{Level: 3},
+		Heading{Level: 2},
+		Heading{Level: 2},
+		Heading{Level: 3},
+	}
+
+	require.Equal(t, exp, Normalise(in))
+}
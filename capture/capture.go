@@ -0,0 +1,91 @@
+// Package capture appends lines to notes under named topics, for quickly
+// capturing thoughts without opening an editor.
+package capture
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+// Format runs the line 's' through the formatter, see ast.FmtString, so it is
+// appended in a consistent style. If 'stamp' is not the zero time, a time
+// artifact, e.g. '$14:30', is appended to the line.
+func Format(s string, stamp time.Time) string {
+	line := ""
+	if notes := notebook.Parse(strings.TrimSpace(s)); len(notes) > 0 {
+		line = strings.TrimSuffix(ast.FmtString(notes[:1]), "\n")
+	}
+
+	if !stamp.IsZero() {
+		line = strings.TrimSpace(line + " $" + stamp.Format("15:04"))
+	}
+	return line
+}
+
+// Insert returns the note 'src' with 'lines' inserted at the end of the
+// section under the topic titled 'topic'. Topics of any level are matched by
+// slug, see ast.Slug, and the first match is used. If there is no such topic
+// then one is appended to the end of the note. Lines outside the section are
+// left untouched.
+func Insert(src, topic string, lines ...string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	text := strings.Split(strings.TrimRight(src, "\n"), "\n")
+	if src == "" {
+		text = []string{}
+	}
+
+	notes := notebook.Parse(strings.Join(text, "\n"))
+	start := findTopic(notes, topic)
+
+	if start < 0 {
+		if len(text) > 0 {
+			text = append(text, "")
+		}
+		text = append(text, "# "+strings.TrimSpace(topic))
+		text = append(text, lines...)
+		return strings.Join(text, "\n") + "\n"
+	}
+
+	end := sectionEnd(notes, start)
+	for end > start+1 && notes[end-1].Type() == ast.EmptyLine {
+		end-- // Insert before the blank lines separating sections
+	}
+
+	r := make([]string, 0, len(text)+len(lines))
+	r = append(r, text[:end]...)
+	r = append(r, lines...)
+	r = append(r, text[end:]...)
+	return strings.Join(r, "\n") + "\n"
+}
+
+// findTopic returns the index of the first topic in 'notes' titled 'topic' or
+// -1 if there isn't one.
+func findTopic(notes ast.Notes, topic string) int {
+	slug := ast.Slug(topic)
+	for i, n := range notes {
+		if isHeading(n) && ast.Slug(n.Text()) == slug {
+			return i
+		}
+	}
+	return -1
+}
+
+// sectionEnd returns the index after the last line of the section starting at
+// the topic at index 'start', i.e. the index of the next topic of the same or
+// a shallower level, or the end of the note.
+func sectionEnd(notes ast.Notes, start int) int {
+	level := ast.Level(notes[start])
+	for i := start + 1; i < len(notes); i++ {
+		if isHeading(notes[i]) && ast.Level(notes[i]) <= level {
+			return i
+		}
+	}
+	return len(notes)
+}
+
+func isHeading(n ast.Node) bool {
+	return n.Type() == ast.Topic || n.Type() == ast.SubTopic
+}
@@ -0,0 +1,32 @@
+package capture
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat_1(t *testing.T) {
+	require.Equal(t, "**key** -bad-", Format("  **key** -bad", time.Time{}))
+
+	stamp := time.Date(2021, 2, 6, 14, 30, 0, 0, time.UTC)
+	require.Equal(t, ".milk $14:30", Format(".milk", stamp))
+}
+
+func TestInsert_1(t *testing.T) {
+	src := "# Shopping\n.milk\n\n# Todo\n.call Bob\n"
+	exp := "# Shopping\n.milk\n.eggs\n\n# Todo\n.call Bob\n"
+	require.Equal(t, exp, Insert(src, "shopping", ".eggs"))
+}
+
+func TestInsert_2(t *testing.T) {
+	src := "# Shopping\n## Dairy\n.milk\n# Todo\n"
+	exp := "# Shopping\n## Dairy\n.milk\n.bread\n# Todo\n"
+	require.Equal(t, exp, Insert(src, "Shopping", ".bread"))
+}
+
+func TestInsert_3(t *testing.T) {
+	require.Equal(t, "# Ideas\nabc\n", Insert("", "Ideas", "abc"))
+	require.Equal(t, "# Todo\n\n# Ideas\nabc\n", Insert("# Todo\n", "Ideas", "abc"))
+}
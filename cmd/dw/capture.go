@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/capture"
+)
+
+func runCapture(args []string) error {
+	fs := flag.NewFlagSet("capture", flag.ExitOnError)
+	note := fs.String("note", "", "note file to append to, created if missing")
+	topic := fs.String("topic", "Inbox", "topic to append under, created if missing")
+	stamp := fs.Bool("stamp", false, "append the time as an artifact, e.g. '$14:30'")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw capture -note file [-topic name] [-stamp] [text...]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Appends the text, or each line of stdin if no text is given, under")
+		fmt.Fprintln(os.Stderr, "the topic.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *note == "" {
+		fs.Usage()
+		return errors.New("no note given")
+	}
+
+	input, e := captureInput(fs.Args())
+	if e != nil {
+		return e
+	}
+
+	var at time.Time
+	if *stamp {
+		at = time.Now()
+	}
+
+	lines := []string{}
+	for _, s := range input {
+		if strings.TrimSpace(s) != "" {
+			lines = append(lines, capture.Format(s, at))
+		}
+	}
+	if len(lines) == 0 {
+		return errors.New("nothing to capture")
+	}
+
+	src, e := ioutil.ReadFile(*note)
+	if e != nil && !os.IsNotExist(e) {
+		return e
+	}
+
+	out := capture.Insert(string(src), *topic, lines...)
+	return ioutil.WriteFile(*note, []byte(out), 0644)
+}
+
+// captureInput returns 'args' joined as a single line or, if there are none,
+// the lines read from stdin.
+func captureInput(args []string) ([]string, error) {
+	if len(args) > 0 {
+		return []string{strings.Join(args, " ")}, nil
+	}
+
+	r := []string{}
+	sc := bufio.NewScanner(os.Stdin)
+	for sc.Scan() {
+		r = append(r, sc.Text())
+	}
+	return r, sc.Err()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/PaulioRandall/daft-wullie-go/consensus"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+	"github.com/PaulioRandall/daft-wullie-go/markdown"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/wiki"
+)
+
+func runConsensus(args []string) error {
+	fs := flag.NewFlagSet("consensus", flag.ExitOnError)
+	format := fs.String("format", "dw", "output format: dw, md, or html")
+	topic := fs.Float64("topic-match", 0.6, "how closely topic titles must match, from 0 to 1")
+	point := fs.Float64("point-match", 0.7, "how closely points must match, from 0 to 1")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw consensus [-format dw|md|html] file file...")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Combines several people's notes of the same event, each named after its file.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return errors.New("expected at least two notes")
+	}
+
+	sources := make([]consensus.Source, fs.NArg())
+	for i, f := range fs.Args() {
+		n, e := notebook.ReadNote(f)
+		if e != nil {
+			return e
+		}
+		sources[i] = consensus.Source{Name: wiki.Name(f), Notes: n.Notes}
+	}
+
+	c := consensus.Combine(sources, consensus.Options{
+		TopicThreshold: *topic,
+		PointThreshold: *point,
+	})
+	doc := consensus.Document(c)
+
+	switch *format {
+	case "dw":
+		fmt.Print(doc)
+	case "md", "markdown":
+		fmt.Print(markdown.Render(notebook.Parse(doc)))
+	case "html":
+		fmt.Print(html.Render(notebook.Parse(doc)))
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+	return nil
+}
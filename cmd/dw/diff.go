@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/PaulioRandall/daft-wullie-go/diff"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := fs.String("format", "text", "output format: text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw diff [-format text|json] old new")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("expected two notes")
+	}
+
+	old, e := notebook.ReadNote(fs.Arg(0))
+	if e != nil {
+		return e
+	}
+	new, e := notebook.ReadNote(fs.Arg(1))
+	if e != nil {
+		return e
+	}
+
+	changes := diff.Notes(old.Notes, new.Notes)
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(changes)
+	case "text":
+		fmt.Print(diff.Text(changes))
+		return nil
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+}
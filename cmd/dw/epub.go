@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/PaulioRandall/daft-wullie-go/epub"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+func runEpub(args []string) error {
+	fs := flag.NewFlagSet("epub", flag.ExitOnError)
+	out := fs.String("o", "", "file to write to instead of stdout")
+	var opts epub.Options
+	fs.StringVar(&opts.Title, "title", "", "title of the book, defaults to the title of a single note")
+	fs.StringVar(&opts.Author, "author", "", "author of the book")
+	fs.StringVar(&opts.Language, "lang", "", "language of the book, defaults to 'en'")
+	fs.StringVar(&opts.ID, "id", "", "unique identifier of the book, e.g. an ISBN URN")
+	date := fs.String("date", "", "date of the book, defaults to the latest date within the notes")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw epub [-title t] [-author a] [-date d] [-lang l] [-id id] [-o file] [files or notebooks]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Packages notes as an EPUB book with one chapter per note, in the order")
+		fmt.Fprintln(os.Stderr, "given. Notebooks add all of their notes in order of path.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *date != "" {
+		t, ok := notebook.ParseDate(*date)
+		if !ok {
+			return fmt.Errorf("invalid date '%s'", *date)
+		}
+		opts.Date = t
+	}
+
+	notes, e := readNotes(fs.Args())
+	if e != nil {
+		return e
+	}
+	if len(notes) == 0 {
+		return errors.New("no notes found")
+	}
+
+	return writeBinary(*out, func() ([]byte, error) {
+		return epub.Render(notes, opts)
+	})
+}
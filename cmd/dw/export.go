@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/asciidoc"
+	"github.com/PaulioRandall/daft-wullie-go/docx"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+	"github.com/PaulioRandall/daft-wullie-go/latex"
+	"github.com/PaulioRandall/daft-wullie-go/markdown"
+	"github.com/PaulioRandall/daft-wullie-go/mindmap"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/odt"
+	"github.com/PaulioRandall/daft-wullie-go/org"
+	"github.com/PaulioRandall/daft-wullie-go/pdf"
+	"github.com/PaulioRandall/daft-wullie-go/rst"
+	"github.com/PaulioRandall/daft-wullie-go/slides"
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "html", "output format: md, html, latex, org, adoc, rst, docx, odt, pdf, slides, dot, or mermaid")
+	out := fs.String("o", "", "file to write to instead of stdout")
+	fragment := fs.Bool("fragment", false, "omit the surrounding document, or the org or rst header")
+	leaves := fs.Bool("leaves", false, "with dot or mermaid, add key phrases and artifacts as leaves")
+	speaker := fs.Bool("notes", false, "with slides, turn text lines into speaker notes")
+	var macros latex.Options
+	fs.StringVar(&macros.KeyPhrase, "key-macro", latex.DefaultKeyPhrase, "latex macro wrapping key phrases")
+	fs.StringVar(&macros.Positive, "positive-macro", latex.DefaultPositive, "latex macro wrapping positive phrases")
+	fs.StringVar(&macros.Negative, "negative-macro", latex.DefaultNegative, "latex macro wrapping negative phrases")
+	fs.StringVar(&macros.Artifact, "artifact-macro", latex.DefaultArtifact, "latex macro wrapping artifacts")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw export [-format md|html|latex|org|adoc|rst|docx|odt|pdf|slides|dot|mermaid] [-fragment] [-notes] [-leaves] [-o file] file")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected one note")
+	}
+
+	n, e := notebook.ReadNote(fs.Arg(0))
+	if e != nil {
+		return e
+	}
+
+	var s string
+	switch *format {
+	case "docx", "word":
+		return writeBinary(*out, func() ([]byte, error) { return docx.Render(n.Notes) })
+	case "odt":
+		return writeBinary(*out, func() ([]byte, error) { return odt.Render(n.Notes) })
+	case "pdf":
+		return writeBinary(*out, func() ([]byte, error) { return pdf.Render(n.Notes) })
+	case "md", "markdown":
+		s = markdown.Render(n.Notes)
+	case "html":
+		if *fragment {
+			s = html.Fragment(n.Notes)
+		} else {
+			s = html.Render(n.Notes)
+		}
+	case "latex", "tex":
+		if *fragment {
+			s = latex.FragmentWith(n.Notes, macros)
+		} else {
+			s = latex.RenderWith(n.Notes, macros)
+		}
+	case "org":
+		if *fragment {
+			s = org.Fragment(n.Notes)
+		} else {
+			s = org.Render(n.Notes)
+		}
+	case "dot", "mermaid":
+		name := filepath.Base(n.Path)
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+		m := mindmap.Build(n.Notes, mindmap.Options{Root: name, Leaves: *leaves})
+		if *format == "dot" {
+			s = mindmap.DOT(m)
+		} else {
+			s = mindmap.Mermaid(m)
+		}
+	case "slides":
+		s = slides.RenderWith(n.Notes, slides.Options{Notes: *speaker})
+	case "adoc", "asciidoc":
+		s = asciidoc.Render(n.Notes)
+	case "rst":
+		if *fragment {
+			s = rst.Fragment(n.Notes)
+		} else {
+			s = rst.Render(n.Notes)
+		}
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+
+	if *out == "" {
+		fmt.Print(s)
+		return nil
+	}
+	return ioutil.WriteFile(*out, []byte(s), 0644)
+}
+
+// writeBinary writes the output of 'render', a binary format such as docx,
+// to 'out', or stdout if 'out' is empty and stdout is not a terminal.
+func writeBinary(out string, render func() ([]byte, error)) error {
+	if out == "" {
+		if info, e := os.Stdout.Stat(); e == nil && info.Mode()&os.ModeCharDevice != 0 {
+			return errors.New("refusing to write a binary format to a terminal, use -o or redirect stdout")
+		}
+	}
+
+	b, e := render()
+	if e != nil {
+		return e
+	}
+
+	if out == "" {
+		_, e = os.Stdout.Write(b)
+		return e
+	}
+	return ioutil.WriteFile(out, b, 0644)
+}
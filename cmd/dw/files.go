@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+// readNotes reads and parses the notes at 'paths'. Directories are opened as
+// notebooks and all of their notes are read. The current directory is used if
+// no paths are given.
+func readNotes(paths []string) ([]*notebook.Note, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	r := []*notebook.Note{}
+	for _, p := range paths {
+		notes, e := readPath(p)
+		if e != nil {
+			return nil, e
+		}
+		r = append(r, notes...)
+	}
+	return r, nil
+}
+
+func readPath(p string) ([]*notebook.Note, error) {
+	info, e := os.Stat(p)
+	if e != nil {
+		return nil, e
+	}
+
+	if !info.IsDir() {
+		n, e := notebook.ReadNote(p)
+		if e != nil {
+			return nil, e
+		}
+		return []*notebook.Note{n}, nil
+	}
+
+	nb, e := notebook.Open(p)
+	if e != nil {
+		return nil, e
+	}
+
+	notes, e := nb.Notes()
+	if e != nil {
+		return nil, e
+	}
+
+	// Report paths relative to the working directory
+	for i, n := range notes {
+		v := *n
+		v.Path = path.Join(filepath.ToSlash(p), n.Path)
+		notes[i] = &v
+	}
+	return notes, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/org"
+)
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "", "input format: org, by default taken from the file extension")
+	out := fs.String("o", "", "file to write the note to instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw import [-format org] [-o file] file")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected one file")
+	}
+
+	b, e := ioutil.ReadFile(fs.Arg(0))
+	if e != nil {
+		return e
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(fs.Arg(0)), ".")
+	}
+
+	var notes ast.Notes
+	switch *format {
+	case "org":
+		notes = org.Import(string(b))
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+
+	s := ast.FmtString(notes)
+	if *out == "" {
+		fmt.Print(s)
+		return nil
+	}
+	return ioutil.WriteFile(*out, []byte(s), 0644)
+}
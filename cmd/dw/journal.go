@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+func runJournal(args []string) error {
+	fs := flag.NewFlagSet("journal", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory holding the journal notes")
+	date := fs.String("date", "", "date of the note, e.g. 2021-02-06, instead of today")
+	tmpl := fs.String("template", "journal", "built in template name or template file")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw journal [-dir path] [-date yyyy-mm-dd] [-template file]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Creates the dated note if it doesn't exist then opens it with $EDITOR,")
+		fmt.Fprintln(os.Stderr, "or prints its path if $EDITOR is not set.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	day := time.Now()
+	if *date != "" {
+		t, ok := notebook.ParseDate(*date)
+		if !ok {
+			return fmt.Errorf("invalid date '%s'", *date)
+		}
+		day = t
+	}
+
+	file := filepath.Join(*dir, day.Format("2006-01-02")+notebook.Ext)
+	if e := createJournal(file, day, *tmpl); e != nil {
+		return e
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		fmt.Println(file)
+		return nil
+	}
+
+	cmd := exec.Command(editor, file)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// createJournal creates the journal note 'file' for 'day' from the template
+// 'tmpl', see findTemplate. Existing notes are left as they are.
+func createJournal(file string, day time.Time, tmpl string) error {
+	if _, e := os.Stat(file); e == nil || !os.IsNotExist(e) {
+		return e
+	}
+
+	tm, e := findTemplate(tmpl, "")
+	if e != nil {
+		return e
+	}
+
+	s, e := tm.Fill(map[string]string{"date": day.Format("2006-01-02")})
+	if e != nil {
+		return e
+	}
+
+	if e := os.MkdirAll(filepath.Dir(file), 0755); e != nil {
+		return e
+	}
+	return ioutil.WriteFile(file, []byte(s), 0644)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/PaulioRandall/daft-wullie-go/wiki"
+)
+
+func runLinks(args []string) error {
+	fs := flag.NewFlagSet("links", flag.ExitOnError)
+	brokenOnly := fs.Bool("broken", false, "only report broken links")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw links [-broken] [path...]")
+		fmt.Fprintln(os.Stderr, "Reports broken wiki links and the backlinks of each note.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	files, e := readNotes(fs.Args())
+	if e != nil {
+		return e
+	}
+
+	notes := make([]wiki.Note, len(files))
+	for i, f := range files {
+		notes[i] = wiki.Note{Path: f.Path, Notes: f.Notes}
+	}
+
+	r := wiki.NewResolver(notes)
+	broken := r.Broken()
+
+	for _, l := range broken {
+		fmt.Printf("%s:%d: broken link [[%s]]: %s\n", l.From, l.Line, l.Target.Text(), l.Problem)
+	}
+
+	if !*brokenOnly {
+		back := r.Backlinks()
+		for _, n := range notes {
+			links := back[n.Path]
+			if len(links) == 0 {
+				continue
+			}
+
+			fmt.Printf("%s (%d backlinks)\n", n.Path, len(links))
+			for _, l := range links {
+				fmt.Printf("\t%s:%d [[%s]]\n", l.From, l.Line, l.Target.Text())
+			}
+		}
+	}
+
+	if len(broken) > 0 {
+		return errors.New(strconv.Itoa(len(broken)) + " broken links")
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/PaulioRandall/daft-wullie-go/lint"
+)
+
+func runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	tmpl := fs.Bool("template", false, "check the files as templates, allowing placeholders")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw lint [-template] [path...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	files, e := readNotes(fs.Args())
+	if e != nil {
+		return e
+	}
+
+	errs := 0
+	for _, f := range files {
+		b, e := ioutil.ReadFile(f.File)
+		if e != nil {
+			return e
+		}
+
+		for _, i := range lint.Check(string(b), lint.Options{Template: *tmpl}) {
+			fmt.Printf("%s:%v\n", f.Path, i)
+			if i.Severity == lint.Error {
+				errs++
+			}
+		}
+	}
+
+	if errs > 0 {
+		return fmt.Errorf("%d errors", errs)
+	}
+	return nil
+}
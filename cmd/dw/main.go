@@ -0,0 +1,77 @@
+// Command dw provides tools for working with Daft Wullie notes.
+//
+// Usage:
+//
+//	dw <command> [arguments]
+//
+// Run 'dw help' for the list of commands.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"tags", "List tags with counts and locations, or filter lines by tag", runTags},
+	{"links", "Report broken wiki links and the backlinks of each note", runLinks},
+	{"journal", "Create or open today's journal note from a template", runJournal},
+	{"capture", "Append a line under a topic of a note", runCapture},
+	{"new", "Create a note from a template", runNew},
+	{"lint", "Check notes or templates for likely mistakes", runLint},
+	{"minutes", "Compile meeting minutes from a meeting note", runMinutes},
+	{"diff", "Compare two versions of a note line by line", runDiff},
+	{"merge", "Merge two versions of a note with their common ancestor", runMerge},
+	{"consensus", "Combine several people's notes of the same event", runConsensus},
+	{"serve", "Preview notes as HTML, refreshing as they change", runServe},
+	{"export", "Render a note as Markdown, HTML, LaTeX, Org, AsciiDoc, rST, Word, ODT, PDF, slides, or a mind map", runExport},
+	{"epub", "Package notes as an EPUB book", runEpub},
+	{"import", "Convert an Org document into a note", runImport},
+	{"build-site", "Render a notebook as a static HTML site", runBuildSite},
+	{"stats", "Count words, topics, and annotations per note and topic", runStats},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			if e := c.run(os.Args[2:]); e != nil {
+				fmt.Fprintln(os.Stderr, "dw "+name+":", e)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "dw: unknown command '%s'\n", name)
+	printUsage()
+	os.Exit(2)
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "\tdw <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "\t%-12s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'dw <command> -h' for help with a command.")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/PaulioRandall/daft-wullie-go/merge"
+)
+
+func runMerge(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	out := fs.String("o", "", "file to write the result to instead of stdout, may be ours")
+	oursLabel := fs.String("ours-label", "ours", "label of our side of conflicts")
+	theirsLabel := fs.String("theirs-label", "theirs", "label of their side of conflicts")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw merge [-o file] [-ours-label l] [-theirs-label l] base ours theirs")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Exits with status 1 if conflicts were marked. As a git merge driver:")
+		fmt.Fprintln(os.Stderr, "\tdriver = dw merge -o %A %O %A %B")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 3 {
+		fs.Usage()
+		return errors.New("expected base, ours, and theirs")
+	}
+
+	texts := make([]string, 3)
+	for i, f := range fs.Args() {
+		b, e := ioutil.ReadFile(f)
+		if e != nil {
+			return e
+		}
+		texts[i] = string(b)
+	}
+
+	r := merge.Merge(texts[0], texts[1], texts[2], merge.Options{
+		OursLabel:   *oursLabel,
+		TheirsLabel: *theirsLabel,
+	})
+
+	if *out == "" {
+		fmt.Print(r.Text)
+	} else if e := ioutil.WriteFile(*out, []byte(r.Text), 0644); e != nil {
+		return e
+	}
+
+	if r.Conflicts > 0 {
+		return fmt.Errorf("%d conflicts", r.Conflicts)
+	}
+	return nil
+}
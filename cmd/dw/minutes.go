@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/PaulioRandall/daft-wullie-go/html"
+	"github.com/PaulioRandall/daft-wullie-go/minutes"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+func runMinutes(args []string) error {
+	fs := flag.NewFlagSet("minutes", flag.ExitOnError)
+	format := fs.String("format", "md", "output format: md, html, or text")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw minutes [-format md|html|text] file")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected one meeting note")
+	}
+
+	n, e := notebook.ReadNote(fs.Arg(0))
+	if e != nil {
+		return e
+	}
+	m := minutes.Compile(n.Notes)
+
+	switch *format {
+	case "md", "markdown":
+		fmt.Print(minutes.Markdown(m))
+	case "html":
+		fmt.Print(html.Document("Minutes: "+m.Title, minutes.HTML(m)))
+	case "text", "txt":
+		fmt.Print(minutes.Plain(m))
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+	return nil
+}
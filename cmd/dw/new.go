@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/lint"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/templates"
+)
+
+// values are placeholder values given as repeated 'name=value' flags.
+type values map[string]string
+
+func (v values) String() string {
+	return fmt.Sprint(map[string]string(v))
+}
+
+func (v values) Set(s string) error {
+	i := strings.Index(s, "=")
+	if i < 1 {
+		return fmt.Errorf("expected name=value, got '%s'", s)
+	}
+	v[strings.TrimSpace(s[:i])] = s[i+1:]
+	return nil
+}
+
+func runNew(args []string) error {
+	vals := values{}
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	name := fs.String("template", "meeting", "built in template name or template file")
+	dir := fs.String("dir", "", "directory of team templates, searched before the built in ones")
+	out := fs.String("o", "", "file to write the note to instead of stdout")
+	list := fs.Bool("list", false, "list the built in templates")
+	fs.Var(vals, "set", "placeholder value as name=value, may be repeated")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw new [-template name] [-dir path] [-set name=value]... [-o file]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Creates a note from a template. Missing values are prompted for when")
+		fmt.Fprintln(os.Stderr, "run from a terminal and 'date' defaults to today.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *list {
+		for _, t := range templates.Builtin() {
+			fmt.Println(t.Name)
+		}
+		return nil
+	}
+
+	tm, e := findTemplate(*name, *dir)
+	if e != nil {
+		return e
+	}
+
+	if e := lintTemplate(tm); e != nil {
+		return e
+	}
+
+	promptValues(tm, vals)
+
+	s, e := tm.Fill(vals)
+	if e != nil {
+		return e
+	}
+
+	if issues := lint.Check(s, lint.Options{}); lint.HasErrors(issues) {
+		return fmt.Errorf("invalid note: %v", issues[0])
+	}
+
+	if *out == "" {
+		fmt.Print(s)
+		return nil
+	}
+
+	if _, e := os.Stat(*out); e == nil {
+		return fmt.Errorf("%s already exists", *out)
+	}
+	return ioutil.WriteFile(*out, []byte(s), 0644)
+}
+
+// findTemplate returns the template 'name' from the directory 'dir', the
+// built in templates, or, failing that, the file 'name'.
+func findTemplate(name, dir string) (templates.Template, error) {
+	if dir != "" {
+		file := filepath.Join(dir, name+notebook.Ext)
+		if _, e := os.Stat(file); e == nil {
+			return templates.Load(file)
+		}
+	}
+
+	if t, ok := templates.Lookup(name); ok {
+		return t, nil
+	}
+
+	if _, e := os.Stat(name); e != nil {
+		return templates.Template{}, fmt.Errorf("no template named '%s'", name)
+	}
+	return templates.Load(name)
+}
+
+// lintTemplate reports the template's lint warnings and returns an error if
+// it has any lint errors.
+func lintTemplate(tm templates.Template) error {
+	issues := lint.Check(tm.Text, lint.Options{Template: true})
+	for _, i := range issues {
+		fmt.Fprintf(os.Stderr, "%s:%v\n", tm.Name, i)
+	}
+	if lint.HasErrors(issues) {
+		return errors.New("invalid template " + tm.Name)
+	}
+	return nil
+}
+
+// promptValues fills in the missing placeholder values of 'vals'. The date
+// defaults to today and other values are read from the terminal, if there is
+// one.
+func promptValues(tm templates.Template, vals values) {
+	if _, ok := vals["date"]; !ok {
+		vals["date"] = time.Now().Format("2006-01-02")
+	}
+
+	if info, e := os.Stdin.Stat(); e != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return // Not a terminal so let Fill report missing values
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	for _, p := range tm.Placeholders() {
+		if _, ok := vals[p.Name]; ok {
+			continue
+		}
+
+		if p.List {
+			fmt.Fprintf(os.Stderr, "%s (comma separated): ", p.Name)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: ", p.Name)
+		}
+
+		s, e := in.ReadString('\n')
+		if e != nil && s == "" {
+			fmt.Fprintln(os.Stderr)
+			return // Let Fill report the missing values
+		}
+		vals[p.Name] = strings.TrimSpace(s)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/preview"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	poll := fs.Duration("poll", 500*time.Millisecond, "how often to check for changed notes")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw serve [-addr host:port] [-poll duration] [path]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Serves a note or notebook as HTML, refreshing pages as notes change.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	path := "."
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	s, e := preview.New(path)
+	if e != nil {
+		return e
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.Watch(*poll, stop)
+
+	fmt.Fprintf(os.Stderr, "Serving %s at http://%s/\n", path, *addr)
+	return http.ListenAndServe(*addr, s)
+}
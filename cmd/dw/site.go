@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/site"
+)
+
+func runBuildSite(args []string) error {
+	fs := flag.NewFlagSet("build-site", flag.ExitOnError)
+	out := fs.String("o", "site", "directory to build the site in")
+	verbose := fs.Bool("v", false, "list the pages written and removed")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw build-site [-o dir] [-v] [notebook]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Renders a notebook as a static HTML site. Only changed notes are rendered")
+		fmt.Fprintln(os.Stderr, "again when the site is rebuilt.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	root := "."
+	if fs.NArg() > 0 {
+		root = fs.Arg(0)
+	}
+
+	nb, e := notebook.Open(root)
+	if e != nil {
+		return e
+	}
+
+	r, e := site.Build(nb, *out)
+	if e != nil {
+		return e
+	}
+
+	if *verbose {
+		for _, p := range r.Written {
+			fmt.Println("wrote", p)
+		}
+		for _, p := range r.Removed {
+			fmt.Println("removed", p)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d pages written, %d removed, %d notes unchanged\n",
+		len(r.Written), len(r.Removed), r.Unchanged)
+	return nil
+}
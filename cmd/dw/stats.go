@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/stats"
+)
+
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	format := fs.String("format", "table", "output format: table or json")
+	topics := fs.Bool("topics", false, "include a row for each topic in the table")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw stats [-format table|json] [-topics] [path...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	files, e := readNotes(fs.Args())
+	if e != nil {
+		return e
+	}
+
+	notes := make([]stats.Note, len(files))
+	for i, f := range files {
+		notes[i] = stats.OfNote(f.Path, f.Notes)
+	}
+	nb := stats.OfNotebook(notes)
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(nb)
+	case "table":
+		printStats(nb, *topics)
+		return nil
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+}
+
+func printStats(nb stats.Notebook, topics bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NOTE\tLINES\tWORDS\tCHARS\tREAD (MIN)\tTOPICS\tKEY\t+VE\t-VE\tTAGS\tKEY/100W")
+
+	row := func(name string, s stats.Stats) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f\t%d\t%d\t%d\t%d\t%d\t%.2f\n",
+			name, s.Lines, s.Words, s.Chars, s.ReadingMinutes, s.Topics(),
+			s.Counts[ast.KeyPhrase], s.Counts[ast.Positive], s.Counts[ast.Negative],
+			s.Counts[ast.Tag], s.Density[ast.KeyPhrase])
+	}
+
+	for _, n := range nb.Notes {
+		row(n.Path, n.Stats)
+		if !topics {
+			continue
+		}
+		for _, t := range n.Topics {
+			row(strings.Repeat("  ", t.Level)+t.Title, t.Stats)
+		}
+	}
+
+	if len(nb.Notes) > 1 {
+		row("TOTAL", nb.Total)
+	}
+	w.Flush()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/tags"
+)
+
+func runTags(args []string) error {
+	fs := flag.NewFlagSet("tags", flag.ExitOnError)
+	filter := fs.String("tag", "", "only print the lines carrying this tag")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dw tags [-tag name] [path...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	files, e := readNotes(fs.Args())
+	if e != nil {
+		return e
+	}
+
+	if *filter != "" {
+		printTagged(files, *filter)
+		return nil
+	}
+
+	tf := make([]tags.File, len(files))
+	for i, f := range files {
+		tf[i] = tags.File{Path: f.Path, Notes: f.Notes}
+	}
+
+	for _, t := range tags.Index(tf) {
+		fmt.Printf("#%s (%d)\n", t.Name, t.Count())
+		for _, loc := range t.Locations {
+			fmt.Printf("\t%s:%d\n", loc.Path, loc.Line)
+		}
+	}
+	return nil
+}
+
+func printTagged(files []*notebook.Note, tag string) {
+	for _, f := range files {
+		lines := tags.Filter(f.Notes, tag)
+		if len(lines) == 0 {
+			continue
+		}
+
+		fmt.Println(f.Path)
+		for _, line := range lines {
+			s := ast.FmtString(ast.Notes{f.Notes[line-1]})
+			fmt.Printf("%6d  %s\n", line, strings.TrimSuffix(s, "\n"))
+		}
+	}
+}
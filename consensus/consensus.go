@@ -0,0 +1,285 @@
+// Package consensus combines several people's notes of the same event, e.g.
+// a meeting, into one. Topics are aligned by title and points, i.e. the lines
+// beneath topics, by text, both using fuzzy matching so small differences in
+// wording, case, or annotation do not matter. Sub topics are only aligned with
+// the sub topics of the topic they are within.
+//
+// Each point of the combined notes records who captured it. Points only one
+// person captured are flagged, as are disagreements, i.e. text one person
+// marked positive and another negative.
+package consensus
+
+import (
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+type (
+	// Source is one person's notes.
+	Source struct {
+		Name  string
+		Notes ast.Notes
+	}
+
+	// Options configures how closely titles and points must match to be
+	// aligned, from zero to one, see Similarity.
+	Options struct {
+		TopicThreshold float64 // Default 0.6
+		PointThreshold float64 // Default 0.7
+	}
+
+	// Consensus is the combined notes.
+	Consensus struct {
+		People []string
+		Topics []*Topic
+	}
+
+	// Topic is a topic aligned across sources. The topic of the lines before
+	// the first topic of each source has no title and a level of zero. Parent
+	// is the topic a sub topic is within, nil for top level topics.
+	Topic struct {
+		Title  string
+		Level  int
+		Parent *Topic
+		By     []string
+		Points []*Point
+	}
+
+	// Point is a line aligned across sources.
+	Point struct {
+		Versions []Version
+	}
+
+	// Version is a person's version of a point.
+	Version struct {
+		Name string
+		Line int // Starting from one
+		Node ast.Node
+	}
+
+	// Disagreement is text that some people marked positive and others
+	// negative.
+	Disagreement struct {
+		Text     string
+		Positive []string
+		Negative []string
+	}
+)
+
+// By returns the names of the people who captured the point.
+func (p *Point) By() []string {
+	r := make([]string, len(p.Versions))
+	for i, v := range p.Versions {
+		r[i] = v.Name
+	}
+	return r
+}
+
+// Single returns true if only one person captured the point.
+func (p *Point) Single() bool {
+	return len(p.Versions) == 1
+}
+
+// Disagreements returns the text within the point that some people marked
+// positive and others negative.
+func (p *Point) Disagreements() []Disagreement {
+	r := []Disagreement{}
+	idx := map[string]int{}
+
+	for _, v := range p.Versions {
+		ast.DecendNode(v.Node, func(n ast.Node, _, _, _ int) {
+			if n.Type() != ast.Positive && n.Type() != ast.Negative {
+				return
+			}
+
+			k := key(n.Text())
+			i, ok := idx[k]
+			if !ok {
+				i = len(r)
+				idx[k] = i
+				r = append(r, Disagreement{Text: strings.TrimSpace(n.Text())})
+			}
+
+			d := &r[i]
+			if n.Type() == ast.Positive {
+				d.Positive = appendName(d.Positive, v.Name)
+			} else {
+				d.Negative = appendName(d.Negative, v.Name)
+			}
+		})
+	}
+
+	n := 0
+	for _, d := range r {
+		if len(d.Positive) > 0 && len(d.Negative) > 0 {
+			r[n] = d
+			n++
+		}
+	}
+	return r[:n]
+}
+
+// Combine aligns the notes of 'sources' into one.
+func Combine(sources []Source, opts Options) *Consensus {
+	if opts.TopicThreshold == 0 {
+		opts.TopicThreshold = 0.6
+	}
+	if opts.PointThreshold == 0 {
+		opts.PointThreshold = 0.7
+	}
+
+	c := &Consensus{People: []string{}, Topics: []*Topic{}}
+	for _, src := range sources {
+		c.People = append(c.People, src.Name)
+		c.add(src, opts)
+	}
+	return c
+}
+
+func (c *Consensus) add(src Source, opts Options) {
+	t := c.topic("", 0, nil, nil, src.Name, opts)
+
+	// Topics the current line is within, with their levels in this source
+	stack := []*Topic{}
+	levels := []int{}
+
+	// Last sub topic of each topic, nil for top level topics
+	last := map[*Topic]*Topic{}
+
+	for i, n := range src.Notes {
+		switch {
+		case n.Type() == ast.Topic || n.Type() == ast.SubTopic:
+			level := ast.Level(n)
+			for len(levels) > 0 && levels[len(levels)-1] >= level {
+				stack, levels = stack[:len(stack)-1], levels[:len(levels)-1]
+			}
+
+			var parent *Topic
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+
+			t = c.topic(strings.TrimSpace(n.Text()), level, parent, last[parent], src.Name, opts)
+			last[parent] = t
+			stack, levels = append(stack, t), append(levels, level)
+
+		case strings.TrimSpace(n.Text()) != "":
+			t.point(Version{Name: src.Name, Line: i + 1, Node: n}, opts)
+		}
+	}
+}
+
+// topic returns the sub topic of 'parent' best matching 'title', adding one
+// after 'prev', the previous sub topic of 'parent' in the same source, if
+// there is no good enough match.
+func (c *Consensus) topic(title string, level int, parent, prev *Topic, name string, opts Options) *Topic {
+	var best *Topic
+	score := opts.TopicThreshold
+
+	for _, t := range c.Topics {
+		if t.Parent != parent || contains(t.By, name) || (t.Title == "") != (title == "") {
+			continue
+		}
+		if title == "" {
+			best = t
+			break
+		}
+		if s := Similarity(t.Title, title); s >= score {
+			best, score = t, s
+		}
+	}
+
+	if best == nil {
+		best = &Topic{Title: title, Level: level, Parent: parent}
+		c.insert(best, prev)
+	}
+	best.By = append(best.By, name)
+	return best
+}
+
+// insert adds 't' to the topics after 'prev' and its sub topics. If 'prev' is
+// nil, 't' is added as the first sub topic of its parent or, if it has none,
+// the first topic after the untitled one.
+func (c *Consensus) insert(t, prev *Topic) {
+	i := 0
+	switch {
+	case prev != nil:
+		i = c.end(prev)
+	case t.Parent != nil:
+		i = c.index(t.Parent) + 1
+	case t.Title != "" && len(c.Topics) > 0 && c.Topics[0].Title == "":
+		i = 1
+	}
+
+	c.Topics = append(c.Topics, nil)
+	copy(c.Topics[i+1:], c.Topics[i:])
+	c.Topics[i] = t
+}
+
+func (c *Consensus) index(t *Topic) int {
+	for i, v := range c.Topics {
+		if v == t {
+			return i
+		}
+	}
+	return -1
+}
+
+// end returns the index after the last sub topic of 't', or after 't' if it
+// has none.
+func (c *Consensus) end(t *Topic) int {
+	i := c.index(t) + 1
+	for ; i < len(c.Topics) && c.Topics[i].within(t); i++ {
+	}
+	return i
+}
+
+// within returns true if 't' is a sub topic of 'parent' at any depth.
+func (t *Topic) within(parent *Topic) bool {
+	for p := t.Parent; p != nil; p = p.Parent {
+		if p == parent {
+			return true
+		}
+	}
+	return false
+}
+
+// point adds 'v' to the point it best matches, adding a point if there is no
+// good enough match.
+func (t *Topic) point(v Version, opts Options) {
+	var best *Point
+	score := opts.PointThreshold
+	text := v.Node.Text()
+
+	for _, p := range t.Points {
+		if contains(p.By(), v.Name) {
+			continue
+		}
+		if s := Similarity(p.Versions[0].Node.Text(), text); s >= score {
+			best, score = p, s
+		}
+	}
+
+	if best == nil {
+		best = &Point{}
+		t.Points = append(t.Points, best)
+	}
+	best.Versions = append(best.Versions, v)
+}
+
+func appendName(names []string, name string) []string {
+	if contains(names, name) {
+		return names
+	}
+	return append(names, name)
+}
+
+func contains(names []string, name string) bool {
+	for _, s := range names {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/PaulioRandall/daft-wullie-go/lint"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+func sources() []Source {
+	return []Source{
+		{"Alice", notebook.Parse("# Rennet\n. Animal rennet is +cheap+\n. Needs a **cloth**\n\n# Curds\n. Cut at 30 mins")},
+		{"Bob", notebook.Parse("# rennet:\n. animal rennet is -cheap-\n\n# Curd\n. cut at 30 minutes\n. Stir gently")},
+		{"Carol", notebook.Parse("# Renet\n. Animal rennet is cheap\n# Budget\n. Too -expensive-")},
+	}
+}
+
+func TestSimilarity_1(t *testing.T) {
+	require.Equal(t, 1.0, Similarity("Rennet", "rennet:"))
+	require.True(t, Similarity("Curds", "Curd") >= 0.6)
+	require.True(t, Similarity("Curds", "Budget") < 0.6)
+	require.Equal(t, 0.0, Similarity("a", "b"))
+}
+
+func TestCombine_1(t *testing.T) {
+	c := Combine(sources(), Options{})
+
+	require.Equal(t, []string{"Alice", "Bob", "Carol"}, c.People)
+	require.Equal(t, 4, len(c.Topics)) // Untitled, Rennet, Budget, Curds
+
+	rennet := c.Topics[1]
+	require.Equal(t, []string{"Alice", "Bob", "Carol"}, rennet.By)
+	require.Equal(t, 2, len(rennet.Points))
+	require.Equal(t, []string{"Alice", "Bob", "Carol"}, rennet.Points[0].By())
+	require.True(t, rennet.Points[1].Single())
+
+	d := rennet.Points[0].Disagreements()
+	require.Equal(t, []Disagreement{{Text: "cheap", Positive: []string{"Alice"}, Negative: []string{"Bob"}}}, d)
+
+	curds := c.Topics[3]
+	require.Equal(t, []string{"Alice", "Bob"}, curds.Points[0].By())
+	require.Equal(t, []string{"Bob"}, curds.Points[1].By())
+}
+
+func TestCombine_2(t *testing.T) {
+	c := Combine([]Source{
+		{"Alice", notebook.Parse("# Budget\n. Costs are high\n## Costs\n. Rent\n# Staffing\n## Costs\n. Salaries")},
+		{"Bob", notebook.Parse("# Budget\n. Costs are high\n## Revenue\n. Grants\n## Costs\n. Rent\n# Staffing\n. Hire two")},
+	}, Options{})
+
+	titles := []string{}
+	for _, t := range c.Topics[1:] {
+		titles = append(titles, t.Title)
+	}
+	require.Equal(t, []string{"Budget", "Revenue", "Costs", "Staffing", "Costs"}, titles)
+
+	budget, revenue, staffing := c.Topics[1], c.Topics[2], c.Topics[4]
+	require.Equal(t, budget, revenue.Parent)
+	require.Equal(t, []string{"Bob"}, revenue.By)
+	require.Equal(t, budget, c.Topics[3].Parent)
+	require.Equal(t, []string{"Alice", "Bob"}, c.Topics[3].By)
+	require.Equal(t, staffing, c.Topics[5].Parent)
+	require.Equal(t, []string{"Alice"}, c.Topics[5].By)
+
+	exp := `# Budget
+. Costs are high $Alice$ $Bob$
+
+## Revenue
+. Grants $Bob$ #single
+
+## Costs
+. Rent $Alice$ $Bob$
+
+# Staffing
+. Hire two $Bob$ #single
+
+## Costs
+. Salaries $Alice$ #single
+`
+	require.Equal(t, exp, Document(c))
+}
+
+func TestDocument_1(t *testing.T) {
+	exp := `# Rennet
+. Animal rennet is +cheap+ $Alice$ $Bob$ $Carol$ #disagreement
+.. $Bob$ animal rennet is -cheap-
+.. $Carol$ Animal rennet is cheap
+. Needs a **cloth** $Alice$ #single
+
+# Budget
+. Too -expensive- $Carol$ #single
+
+# Curds
+. Cut at 30 mins $Alice$ $Bob$
+. Stir gently $Bob$ #single
+`
+	s := Document(Combine(sources(), Options{}))
+	require.Equal(t, exp, s)
+	require.Empty(t, lint.Check(s, lint.Options{}))
+}
@@ -0,0 +1,72 @@
+package consensus
+
+import (
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+// Tags added to flagged points of the combined notes.
+const (
+	TagSingle       = "single"
+	TagDisagreement = "disagreement"
+)
+
+// Document returns the combined notes as a Daft Wullie note. Each point ends
+// with the names of the people who captured it as artifacts. Points only one
+// person captured are tagged '#single' and points with disagreements are
+// tagged '#disagreement' and followed by every other person's version:
+//
+//	. Rennet is +cheap+ $Alice$ $Bob$ #disagreement
+//	.. $Bob$ Rennet is -cheap-
+func Document(c *Consensus) string {
+	sb := &strings.Builder{}
+
+	for _, t := range c.Topics {
+		if t.Title != "" {
+			if sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(strings.Repeat("#", t.Level) + " " + t.Title + "\n")
+		}
+
+		for _, p := range t.Points {
+			writePoint(sb, p)
+		}
+	}
+	return sb.String()
+}
+
+func writePoint(sb *strings.Builder, p *Point) {
+	first := p.Versions[0]
+	sb.WriteString(ast.FmtNode(first.Node))
+	for _, name := range p.By() {
+		sb.WriteString(" " + person(name))
+	}
+
+	disagree := len(p.Disagreements()) > 0
+	switch {
+	case p.Single():
+		sb.WriteString(" #" + TagSingle)
+	case disagree:
+		sb.WriteString(" #" + TagDisagreement)
+	}
+	sb.WriteString("\n")
+
+	if !disagree {
+		return
+	}
+
+	depth := 1
+	if li, ok := ast.AsListItem(first.Node); ok {
+		depth = li.Depth + 1
+	}
+	for _, v := range p.Versions[1:] {
+		sb.WriteString(strings.Repeat(".", depth) + " " + person(v.Name) + " ")
+		sb.WriteString(strings.TrimSpace(ast.FmtChildren(v.Node)) + "\n")
+	}
+}
+
+func person(name string) string {
+	return "$" + ast.EscapeText(name) + "$"
+}
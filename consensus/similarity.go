@@ -0,0 +1,55 @@
+package consensus
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Similarity returns how similar the texts 'a' and 'b' are from zero, nothing
+// in common, to one, the same. Only letters and digits are compared and case
+// is ignored. It is the Sørensen–Dice coefficient of the pairs of adjacent
+// characters within each text.
+func Similarity(a, b string) float64 {
+	ka, kb := key(a), key(b)
+	if ka == kb {
+		return 1
+	}
+
+	pa, pb := bigrams(ka), bigrams(kb)
+	if len(pa) == 0 || len(pb) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, p := range pa {
+		counts[p]++
+	}
+
+	common := 0
+	for _, p := range pb {
+		if counts[p] > 0 {
+			counts[p]--
+			common++
+		}
+	}
+	return float64(2*common) / float64(len(pa)+len(pb))
+}
+
+// key returns the lower case letters and digits of 's'.
+func key(s string) string {
+	return strings.Map(func(ru rune) rune {
+		if unicode.IsLetter(ru) || unicode.IsDigit(ru) {
+			return unicode.ToLower(ru)
+		}
+		return -1
+	}, s)
+}
+
+func bigrams(s string) []string {
+	ru := []rune(s)
+	r := []string{}
+	for i := 0; i+1 < len(ru); i++ {
+		r = append(r, string(ru[i:i+2]))
+	}
+	return r
+}
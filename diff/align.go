@@ -0,0 +1,40 @@
+package diff
+
+import "github.com/PaulioRandall/daft-wullie-go/lcs"
+
+// op pairs a line of the old version with a line of the new version. Either
+// is nil if the line was added or removed.
+type op struct {
+	a, b *line
+}
+
+// align matches the lines of 'a' and 'b' by key using their longest common
+// subsequence and returns the resulting edit script. Removals are placed
+// before additions at the same position.
+func align(a, b []line) []op {
+	keys := func(ls []line) []string {
+		r := make([]string, len(ls))
+		for i, l := range ls {
+			r[i] = l.key
+		}
+		return r
+	}
+
+	r := []op{}
+	j := 0
+	for i, k := range lcs.Match(keys(a), keys(b)) {
+		if k < 0 {
+			r = append(r, op{a: &a[i]})
+			continue
+		}
+		for ; j < k; j++ {
+			r = append(r, op{b: &b[j]})
+		}
+		r = append(r, op{&a[i], &b[j]})
+		j++
+	}
+	for ; j < len(b); j++ {
+		r = append(r, op{b: &b[j]})
+	}
+	return r
+}
@@ -0,0 +1,252 @@
+// Package diff compares two versions of a note line by line. Lines are
+// matched by their text, ignoring annotations and whitespace, so unlike a
+// textual diff it reports when only the annotations of a line changed, e.g.
+// '+cheap+' became '-cheap-', and when a line moved elsewhere in the note.
+// Empty lines and whitespace only edits are ignored.
+package diff
+
+import (
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+// Kind is the kind of change made to a line.
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Moved   Kind = "moved"
+	Changed Kind = "changed"
+)
+
+type (
+	// Change is a change made to a line. Old and New are the formatted
+	// lines, see ast.FmtNode.
+	Change struct {
+		Kind    Kind   `json:"kind"`
+		OldLine int    `json:"oldLine,omitempty"`
+		NewLine int    `json:"newLine,omitempty"`
+		Old     string `json:"old,omitempty"`
+		New     string `json:"new,omitempty"`
+
+		// OldTopic and NewTopic are the titles of the topics containing a
+		// moved line, set only if they differ.
+		OldTopic string `json:"oldTopic,omitempty"`
+		NewTopic string `json:"newTopic,omitempty"`
+
+		// LineType is set if the type of the line changed, e.g. from a bullet
+		// point to a numbered point.
+		LineType *TypeChange `json:"lineType,omitempty"`
+
+		Annotations []Annotation `json:"annotations,omitempty"`
+	}
+
+	// TypeChange is a change in the type of a node.
+	TypeChange struct {
+		From ast.NodeType `json:"from"`
+		To   ast.NodeType `json:"to"`
+	}
+
+	// Annotation is a change to the annotation of some text within a line.
+	// From is empty if the annotation was added and To is empty if it was
+	// removed.
+	Annotation struct {
+		Text string       `json:"text"`
+		From ast.NodeType `json:"from,omitempty"`
+		To   ast.NodeType `json:"to,omitempty"`
+	}
+)
+
+// line is a non-empty line of a note prepared for comparison.
+type line struct {
+	num     int    // Starting from one
+	key     string // Text without whitespace, ignoring annotations
+	fmt     string // Formatted with whitespace normalised
+	cmp     string // Formatted without whitespace
+	topic   string // Title of the enclosing topic
+	node    ast.Node
+	phrases []phrase
+}
+
+// phrase is an annotated piece of text.
+type phrase struct {
+	typ  ast.NodeType
+	text string
+}
+
+// Notes returns the changes between the 'old' and 'new' versions of a note
+// in order of the new version, with removed lines placed where they were.
+func Notes(old, new ast.Notes) []Change {
+	a, b := lines(old), lines(new)
+	changes := []Change{}
+
+	for _, op := range align(a, b) {
+		switch {
+		case op.a == nil:
+			changes = append(changes, Change{Kind: Added, NewLine: op.b.num, New: op.b.fmt})
+		case op.b == nil:
+			changes = append(changes, Change{Kind: Removed, OldLine: op.a.num, Old: op.a.fmt})
+		case op.a.cmp != op.b.cmp:
+			changes = append(changes, changed(*op.a, *op.b))
+		}
+	}
+
+	return findMoves(changes, a, b)
+}
+
+func lines(notes ast.Notes) []line {
+	r := []line{}
+	topic := ""
+
+	for i, n := range notes {
+		key := strings.Join(strings.Fields(n.Text()), "")
+		if n.Type() == ast.Topic || n.Type() == ast.SubTopic {
+			topic = strings.TrimSpace(n.Text())
+			key = "#" + key // Topics never match other lines
+		}
+		if key == "" {
+			continue
+		}
+
+		r = append(r, line{
+			num:     i + 1,
+			key:     key,
+			fmt:     normalise(ast.FmtNode(n)),
+			cmp:     strings.Join(strings.Fields(ast.FmtNode(n)), ""),
+			topic:   topic,
+			node:    n,
+			phrases: phrases(n),
+		})
+	}
+	return r
+}
+
+// phrases returns the annotated text within 'n' in order.
+func phrases(n ast.Node) []phrase {
+	r := []phrase{}
+	var walk func(ast.Node)
+	walk = func(n ast.Node) {
+		switch n.Type() {
+		case ast.KeyPhrase, ast.Positive, ast.Negative, ast.Strong, ast.Quote,
+			ast.Artifact, ast.Snippet, ast.Link, ast.WikiLink, ast.Tag:
+			r = append(r, phrase{typ: n.Type(), text: normalise(n.Text())})
+		}
+		if p, ok := n.(ast.Parent); ok {
+			for _, c := range p.Nodes() {
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	return r
+}
+
+func normalise(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// changed returns the change between the matching lines 'a' and 'b'.
+func changed(a, b line) Change {
+	c := Change{
+		Kind:    Changed,
+		OldLine: a.num,
+		NewLine: b.num,
+		Old:     a.fmt,
+		New:     b.fmt,
+	}
+
+	if a.node.Type() != b.node.Type() || ast.Level(a.node) != ast.Level(b.node) {
+		c.LineType = &TypeChange{From: a.node.Type(), To: b.node.Type()}
+	}
+	c.Annotations = annotations(a.phrases, b.phrases)
+	return c
+}
+
+// annotations returns the changes between the annotated text of two versions
+// of a line. Text annotated in both versions but differently is reported as a
+// change of annotation rather than a removal and addition.
+func annotations(old, new []phrase) []Annotation {
+	used := make([]bool, len(new))
+	take := func(p phrase, sameType bool) int {
+		for i, q := range new {
+			if !used[i] && q.text == p.text && (q.typ == p.typ) == sameType {
+				used[i] = true
+				return i
+			}
+		}
+		return -1
+	}
+
+	rest := []phrase{}
+	for _, p := range old {
+		if take(p, true) < 0 {
+			rest = append(rest, p)
+		}
+	}
+
+	r := []Annotation{}
+	for _, p := range rest {
+		if i := take(p, false); i >= 0 {
+			r = append(r, Annotation{Text: p.text, From: p.typ, To: new[i].typ})
+		} else {
+			r = append(r, Annotation{Text: p.text, From: p.typ})
+		}
+	}
+
+	for i, q := range new {
+		if !used[i] {
+			r = append(r, Annotation{Text: q.text, To: q.typ})
+		}
+	}
+	return r
+}
+
+// findMoves replaces each removed line that was added elsewhere with a move.
+func findMoves(changes []Change, a, b []line) []Change {
+	byNum := func(ls []line, num int) line {
+		for _, l := range ls {
+			if l.num == num {
+				return l
+			}
+		}
+		return line{}
+	}
+
+	moved := map[int]bool{} // Indexes of removals that became moves
+	for i, c := range changes {
+		if c.Kind != Added {
+			continue
+		}
+
+		to := byNum(b, c.NewLine)
+		for j, d := range changes {
+			if d.Kind != Removed || moved[j] {
+				continue
+			}
+
+			from := byNum(a, d.OldLine)
+			if from.key != to.key {
+				continue
+			}
+
+			m := changed(from, to)
+			m.Kind = Moved
+			if from.topic != to.topic {
+				m.OldTopic, m.NewTopic = from.topic, to.topic
+			}
+			changes[i] = m
+			moved[j] = true
+			break
+		}
+	}
+
+	r := []Change{}
+	for i, c := range changes {
+		if !moved[i] {
+			r = append(r, c)
+		}
+	}
+	return r
+}
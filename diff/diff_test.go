@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+func diff(old, new string) []Change {
+	return Notes(notebook.Parse(old), notebook.Parse(new))
+}
+
+func TestNotes_1(t *testing.T) {
+	old := "# Cheese\n. Rennet is  +cheap+\n\n. Milk"
+	new := "# Cheese\n.Rennet is +cheap+\n. Milk\n"
+	require.Empty(t, diff(old, new))
+}
+
+func TestNotes_2(t *testing.T) {
+	changes := diff("# Cheese\n. Rennet is +cheap+", "# Cheese\n! Rennet is **cheap**")
+
+	require.Equal(t, 1, len(changes))
+	c := changes[0]
+	require.Equal(t, Changed, c.Kind)
+	require.Equal(t, 2, c.NewLine)
+	require.Equal(t, &TypeChange{From: ast.BulPoint, To: ast.NumPoint}, c.LineType)
+	require.Equal(t, []Annotation{{Text: "cheap", From: ast.Positive, To: ast.KeyPhrase}}, c.Annotations)
+}
+
+func TestNotes_3(t *testing.T) {
+	old := "# A\n. one\n. two\n# B\n. three"
+	new := "# A\n. two\n. four\n# B\n. three\n. one"
+	changes := diff(old, new)
+
+	require.Equal(t, 2, len(changes))
+	require.Equal(t, Added, changes[0].Kind)
+	require.Equal(t, ". four", changes[0].New)
+	require.Equal(t, Moved, changes[1].Kind)
+	require.Equal(t, 2, changes[1].OldLine)
+	require.Equal(t, 6, changes[1].NewLine)
+	require.Equal(t, "A", changes[1].OldTopic)
+	require.Equal(t, "B", changes[1].NewTopic)
+}
+
+func TestNotes_4(t *testing.T) {
+	changes := diff("a\n**b** c", "a *new*\nb c")
+
+	require.Equal(t, 3, len(changes))
+	require.Equal(t, Removed, changes[0].Kind)
+	require.Equal(t, Added, changes[1].Kind)
+	require.Equal(t, []Annotation{{Text: "b", From: ast.KeyPhrase}}, changes[2].Annotations)
+}
+
+func TestNotes_5(t *testing.T) {
+
+	// Long notes are compared without a table of every pair of lines
+	lines := make([]string, 50000)
+	for i := range lines {
+		lines[i] = ". line " + strconv.Itoa(i)
+	}
+	old := strings.Join(lines, "\n")
+	lines[25000] = ". changed"
+	changes := diff(old, strings.Join(lines, "\n"))
+
+	require.Equal(t, 2, len(changes))
+	require.Equal(t, Removed, changes[0].Kind)
+	require.Equal(t, Added, changes[1].Kind)
+	require.Equal(t, 25001, changes[1].NewLine)
+}
+
+func TestText_1(t *testing.T) {
+	changes := diff("# Cheese\n. Rennet is +cheap+\n. Milk", "# Cheese\n. Rennet is -cheap-")
+	exp := `changed 2 -> 2
+  - . Rennet is +cheap+
+  + . Rennet is -cheap-
+  * 'cheap' positive -> negative
+removed 3
+  - . Milk
+`
+	require.Equal(t, exp, Text(changes))
+
+	js, e := json.Marshal(changes[0])
+	require.Nil(t, e)
+	require.Contains(t, string(js), `"annotations":[{"text":"cheap","from":"Positive","to":"Negative"}]`)
+}
@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+var typeNames = map[ast.NodeType]string{
+	ast.Topic:       "topic",
+	ast.SubTopic:    "sub topic",
+	ast.BulPoint:    "bullet point",
+	ast.SubBulPoint: "sub bullet point",
+	ast.NumPoint:    "numbered point",
+	ast.SubNumPoint: "sub numbered point",
+	ast.TextLine:    "text",
+	ast.KeyPhrase:   "key phrase",
+	ast.Positive:    "positive",
+	ast.Negative:    "negative",
+	ast.Strong:      "strong",
+	ast.Quote:       "quote",
+	ast.Artifact:    "artifact",
+	ast.Snippet:     "snippet",
+	ast.Link:        "link",
+	ast.WikiLink:    "wiki link",
+	ast.Tag:         "tag",
+}
+
+// Text returns a human readable report of 'changes', e.g.
+//
+//	changed 3 -> 4
+//	  - .Rennet is +cheap+
+//	  + .Rennet is -cheap-
+//	  * 'cheap' positive -> negative
+func Text(changes []Change) string {
+	sb := &strings.Builder{}
+	for _, c := range changes {
+		switch c.Kind {
+		case Added:
+			fmt.Fprintf(sb, "added %d\n", c.NewLine)
+		case Removed:
+			fmt.Fprintf(sb, "removed %d\n", c.OldLine)
+		case Moved:
+			fmt.Fprintf(sb, "moved %d -> %d", c.OldLine, c.NewLine)
+			if c.OldTopic != c.NewTopic {
+				fmt.Fprintf(sb, " from '%s' to '%s'", c.OldTopic, c.NewTopic)
+			}
+			sb.WriteString("\n")
+		case Changed:
+			fmt.Fprintf(sb, "changed %d -> %d\n", c.OldLine, c.NewLine)
+		}
+
+		switch {
+		case c.Kind == Moved && c.Old == c.New:
+			sb.WriteString("    " + c.New + "\n")
+		default:
+			if c.Old != "" {
+				sb.WriteString("  - " + c.Old + "\n")
+			}
+			if c.New != "" {
+				sb.WriteString("  + " + c.New + "\n")
+			}
+		}
+
+		if c.LineType != nil {
+			fmt.Fprintf(sb, "  * %s -> %s\n", typeNames[c.LineType.From], typeNames[c.LineType.To])
+		}
+		for _, a := range c.Annotations {
+			sb.WriteString("  * " + describe(a) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func describe(a Annotation) string {
+	switch {
+	case a.From == "":
+		return fmt.Sprintf("'%s' now %s", a.Text, typeNames[a.To])
+	case a.To == "":
+		return fmt.Sprintf("'%s' no longer %s", a.Text, typeNames[a.From])
+	}
+	return fmt.Sprintf("'%s' %s -> %s", a.Text, typeNames[a.From], typeNames[a.To])
+}
@@ -0,0 +1,361 @@
+// Package docx writes parsed notes as Word documents, i.e. Office Open XML
+// WordprocessingML packaged in a zip file, without any external tools.
+//
+// Topics use the built in heading styles, 'Heading1' to 'Heading9', so they
+// appear in Word's navigation pane and tables of contents. Lists use real
+// bulleted and numbered list definitions, each list restarting its numbering.
+// Phrases are given character styles that can be restyled within Word:
+//   - key phrases, 'DW Key Phrase'
+//   - positive phrases, 'DW Positive'
+//   - negative phrases, 'DW Negative'
+//   - artifacts, 'DW Artifact'
+//   - tags, 'DW Tag'
+//   - snippets, 'DW Code'
+//
+// A run can only have one character style so the innermost of nested phrases
+// wins. Strong phrases are made bold and quotes are wrapped in curved double
+// quotes. Wiki links link to the Word document named after the slug of the
+// note and links with unsafe schemes, see html.SafeURL, are rendered as text.
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+)
+
+// epoch is the modification time of each part, the earliest a zip file can
+// record.
+var epoch = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// maxLevel is the deepest level of heading and list Word supports.
+const maxLevel = 9
+
+// styles maps the phrase types to the IDs of their character styles.
+var styles = map[ast.NodeType]string{
+	ast.KeyPhrase: "DWKeyPhrase",
+	ast.Positive:  "DWPositive",
+	ast.Negative:  "DWNegative",
+	ast.Artifact:  "DWArtifact",
+	ast.Tag:       "DWTag",
+	ast.Snippet:   "DWCode",
+}
+
+// Render returns 'notes' as a Word document. The title of the document is
+// the first topic within the notes.
+func Render(notes ast.Notes) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if e := Write(buf, notes); e != nil {
+		return nil, e
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes 'notes' to 'w' as a Word document.
+func Write(w io.Writer, notes ast.Notes) error {
+	d := &document{sb: &strings.Builder{}, bookmarks: map[string]bool{}}
+	for _, b := range block.Build(notes) {
+		d.writeBlock(b)
+	}
+
+	parts := []struct {
+		name string
+		data string
+	}{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", packageRels},
+		{"docProps/core.xml", coreProps(html.Title(notes))},
+		{"word/_rels/document.xml.rels", d.rels()},
+		{"word/document.xml", documentStart + d.sb.String() + documentEnd},
+		{"word/styles.xml", stylesXML},
+		{"word/numbering.xml", d.numbering()},
+	}
+
+	z := zip.NewWriter(w)
+	for _, p := range parts {
+		f, e := z.CreateHeader(&zip.FileHeader{
+			Name:     p.name,
+			Method:   zip.Deflate,
+			Modified: epoch, // Keeps the output deterministic
+		})
+		if e != nil {
+			return e
+		}
+		if _, e := io.WriteString(f, p.data); e != nil {
+			return e
+		}
+	}
+	return z.Close()
+}
+
+// document accumulates the body of the document along with the list
+// instances and hyperlinks it refers to.
+type document struct {
+	sb        *strings.Builder
+	nums      []num           // List instances, numId is the index plus one
+	links     []string        // Hyperlink targets, relationship IDs follow the parts
+	bookmarks map[string]bool // Bookmark names already used
+}
+
+// num is an instance of the bulleted or numbered list definition, see
+// numbering.
+type num struct {
+	ordered bool
+	level   int // Level at which numbering starts
+	start   int
+}
+
+// run holds the formatting of a run of text.
+type run struct {
+	style string
+	bold  bool
+}
+
+func (d *document) writeBlock(b block.Block) {
+	switch v := b.(type) {
+	case block.Heading:
+		level := v.Level
+		if level > maxLevel {
+			level = maxLevel
+		}
+		d.sb.WriteString(`<w:p><w:pPr><w:pStyle w:val="Heading` + strconv.Itoa(level) + `"/></w:pPr>`)
+		if name := bookmark(ast.MakeTextLine(v.Nodes...).Text()); name != "_" && !d.bookmarks[name] {
+			id := strconv.Itoa(len(d.bookmarks))
+			d.bookmarks[name] = true
+			d.sb.WriteString(`<w:bookmarkStart w:id="` + id + `" w:name="` + escape(name) + `"/>`)
+			d.writeNodes(v.Nodes, run{})
+			d.sb.WriteString(`<w:bookmarkEnd w:id="` + id + `"/>`)
+		} else {
+			d.writeNodes(v.Nodes, run{})
+		}
+		d.sb.WriteString("</w:p>")
+
+	case block.Para:
+		d.sb.WriteString("<w:p>")
+		d.writeNodes(v.Nodes, run{})
+		d.sb.WriteString("</w:p>")
+
+	case *block.List:
+		d.writeList(v, 0)
+	}
+}
+
+// writeList writes each item as a list paragraph. Each list is a new
+// instance of the list definition so its numbering restarts, as does each
+// run of items following an item with an explicit number.
+func (d *document) writeList(l *block.List, level int) {
+	if level >= maxLevel {
+		level = maxLevel - 1
+	}
+
+	id := 0
+	for i, item := range l.Items {
+		if i == 0 || l.Ordered && item.Start > 0 {
+			d.nums = append(d.nums, num{ordered: l.Ordered, level: level, start: item.Num})
+			id = len(d.nums)
+		}
+
+		d.sb.WriteString(`<w:p><w:pPr><w:pStyle w:val="ListParagraph"/><w:numPr>`)
+		d.sb.WriteString(`<w:ilvl w:val="` + strconv.Itoa(level) + `"/>`)
+		d.sb.WriteString(`<w:numId w:val="` + strconv.Itoa(id) + `"/>`)
+		d.sb.WriteString(`</w:numPr></w:pPr>`)
+		d.writeNodes(item.Nodes, run{})
+		d.sb.WriteString("</w:p>")
+
+		for _, sub := range item.Lists {
+			d.writeList(sub, level+1)
+		}
+	}
+}
+
+func (d *document) writeNodes(ns []ast.Node, r run) {
+	for _, n := range ns {
+		d.writeNode(n, r)
+	}
+}
+
+func (d *document) writeNode(n ast.Node, r run) {
+
+	writeGroup := func(n ast.Node, r run) {
+		if p, ok := n.(ast.Parent); ok {
+			d.writeNodes(p.Nodes(), r)
+		} else {
+			d.writeRun(n.Text(), r)
+		}
+	}
+
+	switch n.Type() {
+	case ast.KeyPhrase, ast.Positive, ast.Negative, ast.Artifact:
+		r.style = styles[n.Type()]
+		writeGroup(n, r)
+	case ast.Strong:
+		r.bold = true
+		writeGroup(n, r)
+	case ast.Quote:
+		d.writeRun("“", r)
+		writeGroup(n, r)
+		d.writeRun("”", r)
+	case ast.Snippet:
+		r.style = styles[ast.Snippet]
+		d.writeRun(n.Text(), r)
+	case ast.Tag:
+		r.style = styles[ast.Tag]
+		d.writeRun("#"+n.Text(), r)
+	case ast.Link:
+		d.writeLink(n, r)
+	case ast.WikiLink:
+		d.writeWikiLink(n, r)
+	default:
+		writeGroup(n, r)
+	}
+}
+
+func (d *document) writeRun(s string, r run) {
+	if s == "" {
+		return
+	}
+
+	d.sb.WriteString("<w:r>")
+	if r.style != "" || r.bold {
+		d.sb.WriteString("<w:rPr>")
+		if r.style != "" {
+			d.sb.WriteString(`<w:rStyle w:val="` + r.style + `"/>`)
+		}
+		if r.bold {
+			d.sb.WriteString("<w:b/>")
+		}
+		d.sb.WriteString("</w:rPr>")
+	}
+	d.sb.WriteString(`<w:t xml:space="preserve">` + escape(s) + "</w:t></w:r>")
+}
+
+func (d *document) writeLink(n ast.Node, r run) {
+	l, ok := n.(ast.LinkNode)
+	if !ok || !html.SafeURL(l.URL) {
+		d.writeRun(n.Text(), r)
+		return
+	}
+	d.writeHyperlink(l.URL, n.Text(), r)
+}
+
+// writeWikiLink writes a wiki link as a link to the Word document named
+// after the slug of the note and, if given, the bookmark of the topic.
+func (d *document) writeWikiLink(n ast.Node, r run) {
+	l, ok := n.(ast.WikiLinkNode)
+	if !ok {
+		d.writeRun(n.Text(), r)
+		return
+	}
+
+	target := ast.Slug(l.Note) + ".docx"
+	if l.Topic != "" {
+		target += "#" + bookmark(l.Topic)
+	}
+	d.writeHyperlink(target, n.Text(), r)
+}
+
+func (d *document) writeHyperlink(target, text string, r run) {
+	d.links = append(d.links, target)
+	id := "rId" + strconv.Itoa(len(d.links)+2)
+
+	r.style = "Hyperlink"
+	d.sb.WriteString(`<w:hyperlink r:id="` + id + `" w:history="1">`)
+	d.writeRun(text, r)
+	d.sb.WriteString("</w:hyperlink>")
+}
+
+// rels returns the relationships of the document part, i.e. its styles,
+// numbering, and hyperlinks.
+func (d *document) rels() string {
+	sb := &strings.Builder{}
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	sb.WriteString(`<Relationship Id="rId1" Type="` + relTypes + `styles" Target="styles.xml"/>`)
+	sb.WriteString(`<Relationship Id="rId2" Type="` + relTypes + `numbering" Target="numbering.xml"/>`)
+	for i, target := range d.links {
+		sb.WriteString(`<Relationship Id="rId` + strconv.Itoa(i+3) + `" Type="` + relTypes + `hyperlink"`)
+		sb.WriteString(` Target="` + escape(target) + `" TargetMode="External"/>`)
+	}
+	sb.WriteString(`</Relationships>`)
+	return sb.String()
+}
+
+// numbering returns the numbering part defining a bulleted and a numbered
+// list along with the instances of them used by the document.
+func (d *document) numbering() string {
+	sb := &strings.Builder{}
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<w:numbering xmlns:w="` + wordNS + `">`)
+	writeAbstractNum(sb, 0, false)
+	writeAbstractNum(sb, 1, true)
+
+	for i, n := range d.nums {
+		abstract := "0"
+		if n.ordered {
+			abstract = "1"
+		}
+		sb.WriteString(`<w:num w:numId="` + strconv.Itoa(i+1) + `">`)
+		sb.WriteString(`<w:abstractNumId w:val="` + abstract + `"/>`)
+		if n.ordered {
+			sb.WriteString(`<w:lvlOverride w:ilvl="` + strconv.Itoa(n.level) + `">`)
+			sb.WriteString(`<w:startOverride w:val="` + strconv.Itoa(n.start) + `"/>`)
+			sb.WriteString(`</w:lvlOverride>`)
+		}
+		sb.WriteString(`</w:num>`)
+	}
+
+	sb.WriteString(`</w:numbering>`)
+	return sb.String()
+}
+
+// bullets are the bullet characters of each level of a bulleted list.
+var bullets = []string{"•", "◦", "▪"}
+
+func writeAbstractNum(sb *strings.Builder, id int, ordered bool) {
+	sb.WriteString(`<w:abstractNum w:abstractNumId="` + strconv.Itoa(id) + `">`)
+	sb.WriteString(`<w:multiLevelType w:val="multilevel"/>`)
+
+	for i := 0; i < maxLevel; i++ {
+		format, text := "bullet", bullets[i%len(bullets)]
+		if ordered {
+			format, text = "decimal", "%"+strconv.Itoa(i+1)+"."
+		}
+
+		indent := strconv.Itoa(720 * (i + 1))
+		sb.WriteString(`<w:lvl w:ilvl="` + strconv.Itoa(i) + `">`)
+		sb.WriteString(`<w:start w:val="1"/>`)
+		sb.WriteString(`<w:numFmt w:val="` + format + `"/>`)
+		sb.WriteString(`<w:lvlText w:val="` + text + `"/>`)
+		sb.WriteString(`<w:lvlJc w:val="left"/>`)
+		sb.WriteString(`<w:pPr><w:ind w:left="` + indent + `" w:hanging="360"/></w:pPr>`)
+		sb.WriteString(`</w:lvl>`)
+	}
+
+	sb.WriteString(`</w:abstractNum>`)
+}
+
+// bookmark returns the name of the bookmark of a topic. Bookmark names may
+// only contain letters, digits, and underscores and are limited to 40
+// characters.
+func bookmark(topic string) string {
+	s := "_" + strings.ReplaceAll(ast.Slug(topic), "-", "_")
+	if r := []rune(s); len(r) > 40 {
+		s = string(r[:40])
+	}
+	return s
+}
+
+// escape escapes 's' for use as XML text or an attribute value.
+func escape(s string) string {
+	buf := &bytes.Buffer{}
+	xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,99 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/internal/ziptest"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_1(t *testing.T) {
+
+	in := `# Cheese & Wine
+## Types
+. Chedder from $Somerset
+.. **Mild** and *mature*, see ` + "`man cheese`" + `
+. Brie
+-Smelly- but +tasty+ [[Wine#Reds]] #dairy
+See [shop](https://a.org/c?a=1&b=2) not [this](javascript:x)
+
+!3 Curdling
+! Ripening
+!1 Salting`
+
+	b, e := Render(parser.ParseAll(scanner.ScanAll(in)))
+	require.Nil(t, e)
+
+	_, parts := ziptest.Unzip(t, b)
+	for _, name := range []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"docProps/core.xml",
+		"word/_rels/document.xml.rels",
+		"word/document.xml",
+		"word/styles.xml",
+		"word/numbering.xml",
+	} {
+		s, ok := parts[name]
+		require.True(t, ok, name)
+		ziptest.WellFormed(t, name, s)
+	}
+
+	doc := parts["word/document.xml"]
+	require.Contains(t, doc, `<w:pStyle w:val="Heading1"/></w:pPr><w:bookmarkStart w:id="0" w:name="_cheese_wine"/><w:r><w:t xml:space="preserve">Cheese &amp; Wine</w:t></w:r>`)
+	require.Contains(t, doc, `<w:pStyle w:val="Heading2"/>`)
+	require.Contains(t, doc, `<w:ilvl w:val="0"/><w:numId w:val="1"/>`)
+	require.Contains(t, doc, `<w:ilvl w:val="1"/><w:numId w:val="2"/>`)
+	require.Contains(t, doc, `<w:ilvl w:val="0"/><w:numId w:val="3"/>`)
+	require.Contains(t, doc, `<w:ilvl w:val="0"/><w:numId w:val="4"/>`)
+	require.Contains(t, doc, `<w:rStyle w:val="DWArtifact"/></w:rPr><w:t xml:space="preserve">Somerset</w:t>`)
+	require.Contains(t, doc, `<w:rStyle w:val="DWKeyPhrase"/></w:rPr><w:t xml:space="preserve">Mild</w:t>`)
+	require.Contains(t, doc, `<w:rPr><w:b/></w:rPr><w:t xml:space="preserve">mature</w:t>`)
+	require.Contains(t, doc, `<w:rStyle w:val="DWCode"/></w:rPr><w:t xml:space="preserve">man cheese</w:t>`)
+	require.Contains(t, doc, `<w:rStyle w:val="DWNegative"/></w:rPr><w:t xml:space="preserve">Smelly</w:t>`)
+	require.Contains(t, doc, `<w:rStyle w:val="DWPositive"/></w:rPr><w:t xml:space="preserve">tasty</w:t>`)
+	require.Contains(t, doc, `<w:rStyle w:val="DWTag"/></w:rPr><w:t xml:space="preserve">#dairy</w:t>`)
+	require.Contains(t, doc, `<w:hyperlink r:id="rId3" w:history="1">`)
+	require.Contains(t, doc, `<w:hyperlink r:id="rId4" w:history="1">`)
+	require.Contains(t, doc, `<w:t xml:space="preserve">this</w:t>`)
+	require.NotContains(t, doc, "rId5")
+
+	rels := parts["word/_rels/document.xml.rels"]
+	require.Contains(t, rels, `Id="rId3" Type="`+relTypes+`hyperlink" Target="wine.docx#_reds" TargetMode="External"`)
+	require.Contains(t, rels, `Id="rId4" Type="`+relTypes+`hyperlink" Target="https://a.org/c?a=1&amp;b=2" TargetMode="External"`)
+
+	num := parts["word/numbering.xml"]
+	require.Contains(t, num, `<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>`)
+	require.Contains(t, num, `<w:num w:numId="2"><w:abstractNumId w:val="0"/></w:num>`)
+	require.Contains(t, num, `<w:num w:numId="3"><w:abstractNumId w:val="1"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="3"/></w:lvlOverride></w:num>`)
+	require.Contains(t, num, `<w:num w:numId="4"><w:abstractNumId w:val="1"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride></w:num>`)
+	require.NotContains(t, num, `w:numId="5"`)
+
+	require.Contains(t, parts["docProps/core.xml"], `<dc:title>Cheese &amp; Wine</dc:title>`)
+}
+
+func TestRender_2(t *testing.T) {
+	notes := parser.ParseAll(scanner.ScanAll("# Title\nSome text"))
+	a, e := Render(notes)
+	require.Nil(t, e)
+	b, e := Render(notes)
+	require.Nil(t, e)
+	require.Equal(t, a, b)
+}
+
+func TestRender_3(t *testing.T) {
+
+	// Only the first of the topics with the same bookmark name is bookmarked,
+	// as links to the topic can only go to one of them
+	b, e := Render(parser.ParseAll(scanner.ScanAll("# Cheese\n## Uses\n# Wine\n## Uses")))
+	require.Nil(t, e)
+
+	_, parts := ziptest.Unzip(t, b)
+	doc := parts["word/document.xml"]
+	require.Equal(t, 1, strings.Count(doc, `w:name="_uses"`))
+	require.Contains(t, doc, `<w:bookmarkStart w:id="2" w:name="_wine"/>`)
+}
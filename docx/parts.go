@@ -0,0 +1,89 @@
+package docx
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+const (
+	wordNS   = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	relNS    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	relTypes = relNS + "/"
+)
+
+const contentTypes = xml.Header +
+	`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+	`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+	`</Types>`
+
+const packageRels = xml.Header +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="` + relTypes + `officeDocument" Target="word/document.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+	`</Relationships>`
+
+const documentStart = xml.Header +
+	`<w:document xmlns:w="` + wordNS + `" xmlns:r="` + relNS + `"><w:body>`
+
+// documentEnd closes the body with an A4 page with one inch margins.
+const documentEnd = `<w:sectPr>` +
+	`<w:pgSz w:w="11906" w:h="16838"/>` +
+	`<w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="708" w:footer="708" w:gutter="0"/>` +
+	`</w:sectPr></w:body></w:document>`
+
+// coreProps returns the core properties part holding the title of the
+// document.
+func coreProps(title string) string {
+	return xml.Header +
+		`<cp:coreProperties` +
+		` xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"` +
+		` xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+		`<dc:title>` + escape(title) + `</dc:title>` +
+		`</cp:coreProperties>`
+}
+
+// stylesXML defines the paragraph styles of topics and list items and the
+// character styles of phrases. The colours match those of html.Style.
+var stylesXML = xml.Header +
+	`<w:styles xmlns:w="` + wordNS + `">` +
+	`<w:docDefaults>` +
+	`<w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="Calibri" w:cs="Calibri"/><w:sz w:val="22"/><w:szCs w:val="22"/><w:lang w:val="en-GB"/></w:rPr></w:rPrDefault>` +
+	`<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="264" w:lineRule="auto"/></w:pPr></w:pPrDefault>` +
+	`</w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+	heading(1, 32) + heading(2, 28) + heading(3, 26) + heading(4, 24) + heading(5, 22) +
+	heading(6, 22) + heading(7, 22) + heading(8, 22) + heading(9, 22) +
+	`<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:spacing w:after="40"/><w:contextualSpacing/></w:pPr></w:style>` +
+	`<w:style w:type="character" w:default="1" w:styleId="DefaultParagraphFont"><w:name w:val="Default Paragraph Font"/><w:uiPriority w:val="1"/><w:semiHidden/></w:style>` +
+	`<w:style w:type="character" w:styleId="DWKeyPhrase"><w:name w:val="DW Key Phrase"/><w:basedOn w:val="DefaultParagraphFont"/><w:qFormat/>` +
+	`<w:rPr><w:shd w:val="clear" w:color="auto" w:fill="FFF3A0"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="DWPositive"><w:name w:val="DW Positive"/><w:basedOn w:val="DefaultParagraphFont"/><w:qFormat/>` +
+	`<w:rPr><w:color w:val="1A7F37"/><w:u w:val="single"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="DWNegative"><w:name w:val="DW Negative"/><w:basedOn w:val="DefaultParagraphFont"/><w:qFormat/>` +
+	`<w:rPr><w:color w:val="CF222E"/><w:u w:val="wave"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="DWArtifact"><w:name w:val="DW Artifact"/><w:basedOn w:val="DefaultParagraphFont"/><w:qFormat/>` +
+	`<w:rPr><w:i/><w:color w:val="6639BA"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="DWTag"><w:name w:val="DW Tag"/><w:basedOn w:val="DefaultParagraphFont"/><w:qFormat/>` +
+	`<w:rPr><w:color w:val="0969DA"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="DWCode"><w:name w:val="DW Code"/><w:basedOn w:val="DefaultParagraphFont"/><w:qFormat/>` +
+	`<w:rPr><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/><w:shd w:val="clear" w:color="auto" w:fill="F0F0F0"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/><w:basedOn w:val="DefaultParagraphFont"/><w:uiPriority w:val="99"/><w:unhideWhenUsed/>` +
+	`<w:rPr><w:color w:val="0563C1"/><w:u w:val="single"/></w:rPr></w:style>` +
+	`</w:styles>`
+
+// heading returns the paragraph style of a topic at 'level' with a font
+// size of 'halfPoints'.
+func heading(level, halfPoints int) string {
+	n, sz := strconv.Itoa(level), strconv.Itoa(halfPoints)
+	return `<w:style w:type="paragraph" w:styleId="Heading` + n + `">` +
+		`<w:name w:val="heading ` + n + `"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:uiPriority w:val="9"/><w:qFormat/>` +
+		`<w:pPr><w:keepNext/><w:keepLines/><w:spacing w:before="240" w:after="80"/><w:outlineLvl w:val="` + strconv.Itoa(level-1) + `"/></w:pPr>` +
+		`<w:rPr><w:b/><w:color w:val="1F3864"/><w:sz w:val="` + sz + `"/><w:szCs w:val="` + sz + `"/></w:rPr>` +
+		`</w:style>`
+}
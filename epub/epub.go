@@ -0,0 +1,356 @@
+// Package epub packages notes as an EPUB 3 book, one chapter per note, for
+// reading on e-readers.
+//
+// Each chapter is the note rendered by the html package as XHTML. The
+// navigation document lists the topics and sub topics of every chapter,
+// nested by level, and the embedded stylesheet styles the same classes as
+// html.Style. Wiki links between notes within the book link to the linked
+// chapter, other wiki links are marked as broken.
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	gohtml "html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/wiki"
+)
+
+// MimeType is the media type of EPUB files.
+const MimeType = "application/epub+zip"
+
+// epoch is the modification time of each part, the earliest a zip file can
+// record.
+var epoch = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Options holds the metadata of a book. Empty fields are derived from the
+// notes, see Resolve.
+type Options struct {
+	Title    string
+	Author   string // Omitted if empty
+	Language string
+	Date     time.Time
+	ID       string // Unique identifier, e.g. a URN or ISBN
+}
+
+// Resolve returns 'opts' with its empty fields derived from 'notes':
+//   - Title is the title of the note if there is only one, otherwise "Notes"
+//   - Language is 'en'
+//   - Date is the latest date of the notes, see notebook.Meta, or, if none,
+//     the latest modification time
+//   - ID is a UUID URN derived from the title and paths of the notes
+func (opts Options) Resolve(notes []*notebook.Note) Options {
+	if opts.Title == "" {
+		opts.Title = "Notes"
+		if len(notes) == 1 {
+			opts.Title = notes[0].Meta.Title
+		}
+	}
+
+	if opts.Language == "" {
+		opts.Language = "en"
+	}
+
+	if opts.Date.IsZero() {
+		opts.Date = latest(notes, func(n *notebook.Note) time.Time { return n.Meta.Date })
+	}
+	if opts.Date.IsZero() {
+		opts.Date = latest(notes, func(n *notebook.Note) time.Time { return n.ModTime })
+	}
+
+	if opts.ID == "" {
+		h := sha1.New()
+		h.Write([]byte(opts.Title + "\n"))
+		for _, n := range notes {
+			h.Write([]byte(n.Path + "\n"))
+		}
+		opts.ID = uuid(h.Sum(nil))
+	}
+
+	return opts
+}
+
+// Render returns 'notes' packaged as an EPUB book.
+func Render(notes []*notebook.Note, opts Options) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if e := Write(buf, notes, opts); e != nil {
+		return nil, e
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes 'notes' to 'w' packaged as an EPUB book.
+func Write(w io.Writer, notes []*notebook.Note, opts Options) error {
+	opts = opts.Resolve(notes)
+
+	type part struct {
+		name   string
+		method uint16
+		data   string
+	}
+
+	parts := []part{
+		{"mimetype", zip.Store, MimeType}, // Must be first and uncompressed
+		{"META-INF/container.xml", zip.Deflate, container},
+		{"OEBPS/content.opf", zip.Deflate, pkg(notes, opts)},
+		{"OEBPS/nav.xhtml", zip.Deflate, nav(notes, opts)},
+		{"OEBPS/style.css", zip.Deflate, Style},
+	}
+
+	res := resolver(notes)
+	for i := range notes {
+		parts = append(parts, part{"OEBPS/" + chapterFile(i), zip.Deflate, chapter(notes, i, res, opts)})
+	}
+
+	z := zip.NewWriter(w)
+	for _, p := range parts {
+		f, e := z.CreateHeader(&zip.FileHeader{
+			Name:     p.name,
+			Method:   p.method,
+			Modified: epoch, // Keeps the output deterministic
+		})
+		if e != nil {
+			return e
+		}
+		if _, e := io.WriteString(f, p.data); e != nil {
+			return e
+		}
+	}
+	return z.Close()
+}
+
+// Style is the stylesheet embedded in each book.
+const Style = `body { font-family: serif; line-height: 1.5; }
+h1, h2, h3, h4, h5, h6 { font-family: sans-serif; line-height: 1.2; }
+code { font-family: monospace; }
+.dw-wiki-link { text-decoration: none; }
+` + html.Style
+
+const container = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
+
+// pkg returns the package document listing the metadata, files, and reading
+// order of the book.
+func pkg(notes []*notebook.Note, opts Options) string {
+	sb := &strings.Builder{}
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(`<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="` + escape(opts.Language) + `">` + "\n")
+
+	sb.WriteString(`<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">` + "\n")
+	sb.WriteString(`<dc:identifier id="book-id">` + escape(opts.ID) + "</dc:identifier>\n")
+	sb.WriteString("<dc:title>" + escape(opts.Title) + "</dc:title>\n")
+	if opts.Author != "" {
+		sb.WriteString("<dc:creator>" + escape(opts.Author) + "</dc:creator>\n")
+	}
+	sb.WriteString("<dc:language>" + escape(opts.Language) + "</dc:language>\n")
+	if !opts.Date.IsZero() {
+		sb.WriteString("<dc:date>" + opts.Date.Format("2006-01-02") + "</dc:date>\n")
+	}
+	sb.WriteString(`<meta property="dcterms:modified">` + modified(notes, opts).Format("2006-01-02T15:04:05Z") + "</meta>\n")
+	sb.WriteString("</metadata>\n")
+
+	sb.WriteString("<manifest>\n")
+	sb.WriteString(`<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>` + "\n")
+	sb.WriteString(`<item id="style" href="style.css" media-type="text/css"/>` + "\n")
+	for i := range notes {
+		sb.WriteString(`<item id="` + chapterID(i) + `" href="` + chapterFile(i) + `" media-type="application/xhtml+xml"/>` + "\n")
+	}
+	sb.WriteString("</manifest>\n")
+
+	sb.WriteString("<spine>\n")
+	for i := range notes {
+		sb.WriteString(`<itemref idref="` + chapterID(i) + `"/>` + "\n")
+	}
+	sb.WriteString("</spine>\n")
+
+	sb.WriteString("</package>\n")
+	return sb.String()
+}
+
+// nav returns the navigation document listing the topics of each chapter. A
+// chapter without topics is listed by the title of its note.
+func nav(notes []*notebook.Note, opts Options) string {
+	sb := &strings.Builder{}
+	sb.WriteString(`<nav epub:type="toc" id="toc">` + "\n")
+	sb.WriteString("<h1>" + escape(opts.Title) + "</h1>\n")
+	sb.WriteString("<ol>\n")
+
+	for i, n := range notes {
+		entries := outline(n.Notes)
+		if len(entries) == 0 {
+			entries = []*entry{{title: n.Meta.Title}}
+		}
+		for _, en := range entries {
+			writeEntry(sb, en, chapterFile(i))
+		}
+	}
+
+	sb.WriteString("</ol>\n</nav>\n")
+	return xhtml(opts.Title, opts.Language, sb.String())
+}
+
+// entry is a topic within the navigation document.
+type entry struct {
+	title   string
+	anchor  string
+	level   int
+	entries []*entry
+}
+
+// outline returns the topics of 'notes' with each nested within the nearest
+// preceding topic of a lower level. Topics are anchored as the html package
+// anchors the headings of chapters, see ast.Anchors.
+func outline(notes ast.Notes) []*entry {
+	root := &entry{}
+	stack := []*entry{root}
+	anchors := ast.Anchors{}
+
+	for _, n := range notes {
+		h, ok := ast.AsHeading(n)
+		if !ok {
+			continue
+		}
+		title := strings.TrimSpace(h.Text())
+		anchor := anchors.Next(title)
+		if title == "" {
+			continue
+		}
+
+		for len(stack) > 1 && stack[len(stack)-1].level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+
+		en := &entry{title: title, anchor: anchor, level: h.Level}
+		parent := stack[len(stack)-1]
+		parent.entries = append(parent.entries, en)
+		stack = append(stack, en)
+	}
+
+	return root.entries
+}
+
+func writeEntry(sb *strings.Builder, en *entry, file string) {
+	href := file
+	if en.anchor != "" {
+		href += "#" + en.anchor
+	}
+
+	sb.WriteString(`<li><a href="` + escape(href) + `">` + escape(en.title) + "</a>")
+	if len(en.entries) > 0 {
+		sb.WriteString("\n<ol>\n")
+		for _, sub := range en.entries {
+			writeEntry(sb, sub, file)
+		}
+		sb.WriteString("</ol>\n")
+	}
+	sb.WriteString("</li>\n")
+}
+
+// chapter returns the XHTML document of the 'i'th note.
+func chapter(notes []*notebook.Note, i int, res *wiki.Resolver, opts Options) string {
+	n := notes[i]
+
+	chapters := map[string]string{}
+	for j, other := range notes {
+		chapters[other.Path] = chapterFile(j)
+	}
+
+	body := html.FragmentWith(n.Notes, html.Options{
+		WikiHref: func(l ast.WikiLinkNode) (string, bool) {
+			link := res.Resolve(n.Path, l)
+			if link.Broken() {
+				return "", false
+			}
+			href := chapters[link.To]
+			if link.Anchor != "" {
+				href += "#" + link.Anchor
+			}
+			return href, true
+		},
+	})
+
+	return xhtml(n.Meta.Title, opts.Language, `<section epub:type="chapter">`+"\n"+body+"</section>\n")
+}
+
+// xhtml wraps the XHTML 'body' in a complete XHTML document.
+func xhtml(title, lang, body string) string {
+	sb := &strings.Builder{}
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString("<!DOCTYPE html>\n")
+	sb.WriteString(`<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops"`)
+	sb.WriteString(` xml:lang="` + escape(lang) + `" lang="` + escape(lang) + `">` + "\n")
+	sb.WriteString("<head>\n")
+	sb.WriteString(`<meta charset="utf-8"/>` + "\n")
+	sb.WriteString("<title>" + escape(title) + "</title>\n")
+	sb.WriteString(`<link rel="stylesheet" type="text/css" href="style.css"/>` + "\n")
+	sb.WriteString("</head>\n<body>\n")
+	sb.WriteString(body)
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+func resolver(notes []*notebook.Note) *wiki.Resolver {
+	wn := make([]wiki.Note, len(notes))
+	for i, n := range notes {
+		wn[i] = wiki.Note{Path: n.Path, Notes: n.Notes}
+	}
+	return wiki.NewResolver(wn)
+}
+
+// modified returns when the book was last modified, i.e. the latest
+// modification time of its notes or, if unknown, its date.
+func modified(notes []*notebook.Note, opts Options) time.Time {
+	t := latest(notes, func(n *notebook.Note) time.Time { return n.ModTime })
+	if t.IsZero() {
+		t = opts.Date
+	}
+	if t.IsZero() {
+		t = epoch
+	}
+	return t.UTC().Truncate(time.Second)
+}
+
+func latest(notes []*notebook.Note, f func(*notebook.Note) time.Time) time.Time {
+	var r time.Time
+	for _, n := range notes {
+		if t := f(n); t.After(r) {
+			r = t
+		}
+	}
+	return r
+}
+
+// uuid formats the first 16 bytes of the hash 'h' as a name based UUID URN.
+func uuid(h []byte) string {
+	b := make([]byte, 16)
+	copy(b, h)
+	b[6] = b[6]&0x0f | 0x50 // Version 5
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func chapterFile(i int) string {
+	return chapterID(i) + ".xhtml"
+}
+
+func chapterID(i int) string {
+	return "chapter-" + strconv.Itoa(i+1)
+}
+
+func escape(s string) string {
+	return gohtml.EscapeString(s)
+}
@@ -0,0 +1,115 @@
+package epub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/internal/ziptest"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+
+	"github.com/stretchr/testify/require"
+)
+
+func note(path, s string) *notebook.Note {
+	notes := notebook.Parse(s)
+	return &notebook.Note{Path: path, Notes: notes, Meta: notebook.MetaOf(path, notes)}
+}
+
+func TestRender_1(t *testing.T) {
+
+	notes := []*notebook.Note{
+		note("cheese.dw", `# Cheese & Wine
+Made on $2021-02-06
+## Types
+### Hard
+## Pairings
+See [[Wine#Reds]] and [[Crackers]]`),
+		note("wine.dw", `# Wine
+## Reds
++Fruity+ and -dry-`),
+		note("2021-03-01.dw", "Just text"),
+	}
+
+	b, e := Render(notes, Options{Author: "Paul <R>"})
+	require.Nil(t, e)
+
+	names, parts := ziptest.Unzip(t, b)
+	require.True(t, ziptest.Stored(t, b))
+	require.Equal(t, []string{
+		"mimetype",
+		"META-INF/container.xml",
+		"OEBPS/content.opf",
+		"OEBPS/nav.xhtml",
+		"OEBPS/style.css",
+		"OEBPS/chapter-1.xhtml",
+		"OEBPS/chapter-2.xhtml",
+		"OEBPS/chapter-3.xhtml",
+	}, names)
+	require.Equal(t, MimeType, parts["mimetype"])
+	for _, name := range names {
+		if name != "mimetype" && name != "OEBPS/style.css" {
+			ziptest.WellFormed(t, name, parts[name])
+		}
+	}
+
+	opf := parts["OEBPS/content.opf"]
+	require.Contains(t, opf, "<dc:title>Notes</dc:title>")
+	require.Contains(t, opf, "<dc:creator>Paul &lt;R&gt;</dc:creator>")
+	require.Contains(t, opf, "<dc:language>en</dc:language>")
+	require.Contains(t, opf, "<dc:date>2021-03-01</dc:date>")
+	require.Contains(t, opf, `<meta property="dcterms:modified">2021-03-01T00:00:00Z</meta>`)
+	require.Contains(t, opf, `<item id="chapter-3" href="chapter-3.xhtml" media-type="application/xhtml+xml"/>`)
+	require.Contains(t, opf, "<spine>\n"+`<itemref idref="chapter-1"/>`+"\n"+`<itemref idref="chapter-2"/>`+"\n"+`<itemref idref="chapter-3"/>`+"\n</spine>")
+
+	exp := "<ol>\n" +
+		`<li><a href="chapter-1.xhtml#cheese-wine">Cheese &amp; Wine</a>` + "\n<ol>\n" +
+		`<li><a href="chapter-1.xhtml#types">Types</a>` + "\n<ol>\n" +
+		`<li><a href="chapter-1.xhtml#hard">Hard</a></li>` + "\n</ol>\n</li>\n" +
+		`<li><a href="chapter-1.xhtml#pairings">Pairings</a></li>` + "\n</ol>\n</li>\n" +
+		`<li><a href="chapter-2.xhtml#wine">Wine</a>` + "\n<ol>\n" +
+		`<li><a href="chapter-2.xhtml#reds">Reds</a></li>` + "\n</ol>\n</li>\n" +
+		`<li><a href="chapter-3.xhtml">2021-03-01</a></li>` + "\n" +
+		"</ol>\n"
+	require.Contains(t, parts["OEBPS/nav.xhtml"], exp)
+
+	ch := parts["OEBPS/chapter-1.xhtml"]
+	require.Contains(t, ch, `<link rel="stylesheet" type="text/css" href="style.css"/>`)
+	require.Contains(t, ch, `<a class="dw-wiki-link" href="chapter-2.xhtml#reds">Wine#Reds</a>`)
+	require.Contains(t, ch, `<span class="dw-wiki-link dw-broken">Crackers</span>`)
+	require.Contains(t, parts["OEBPS/chapter-2.xhtml"], `<span class="dw-positive">Fruity</span>`)
+	require.Contains(t, parts["OEBPS/style.css"], ".dw-negative")
+}
+
+func TestRender_2(t *testing.T) {
+
+	// Repeated and empty topics are anchored as the chapter's headings are
+	notes := []*notebook.Note{note("cheese.dw", "# Cheese\n## Soft\n### Uses\n##\n## Hard\n### Uses")}
+
+	b, e := Render(notes, Options{})
+	require.Nil(t, e)
+	_, parts := ziptest.Unzip(t, b)
+
+	nav := parts["OEBPS/nav.xhtml"]
+	require.Contains(t, nav, `<li><a href="chapter-1.xhtml#uses">Uses</a></li>`)
+	require.Contains(t, nav, `<li><a href="chapter-1.xhtml#uses-1">Uses</a></li>`)
+
+	ch := parts["OEBPS/chapter-1.xhtml"]
+	require.Contains(t, ch, `<h3 id="uses">Uses</h3>`)
+	require.Contains(t, ch, `<h2 id="topic"></h2>`)
+	require.Contains(t, ch, `<h3 id="uses-1">Uses</h3>`)
+}
+
+func TestResolve_1(t *testing.T) {
+	mod := time.Date(2022, 5, 6, 7, 8, 9, 0, time.UTC)
+	n := note("cheese.dw", "# Cheese")
+	n.ModTime = mod
+
+	opts := Options{}.Resolve([]*notebook.Note{n})
+	require.Equal(t, "Cheese", opts.Title)
+	require.Equal(t, "en", opts.Language)
+	require.Equal(t, mod, opts.Date)
+	require.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, opts.ID)
+
+	given := Options{Title: "Book", Language: "fr", Date: mod.AddDate(1, 0, 0), ID: "isbn:1"}
+	require.Equal(t, given, given.Resolve([]*notebook.Note{n}))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/PaulioRandall/daft-wullie-go/ast"
 	"github.com/PaulioRandall/daft-wullie-go/parser"
 	"github.com/PaulioRandall/daft-wullie-go/scanner"
+	"github.com/PaulioRandall/daft-wullie-go/stats"
 )
 
 func main() {
@@ -32,19 +33,7 @@ A sentence with a **keyword** in it
 	tks := scanner.ScanAll(example)
 	notes := parser.ParseAll(tks)
 
-	hCount, pCount, nCount := 0, 0, 0
-	f := func(n ast.Node, lineNum, depth, orderIdx int) {
-		switch n.Type() {
-		case ast.Topic, ast.SubTopic:
-			hCount++
-		case ast.Positive:
-			pCount++
-		case ast.Negative:
-			nCount++
-		}
-	}
-
-	ast.DescendNotes(notes, f)
+	s := stats.Of(notes)
 
 	fmt.Println()
 	fmt.Print("```")
@@ -52,7 +41,8 @@ A sentence with a **keyword** in it
 	fmt.Println("```")
 	fmt.Println()
 	fmt.Println("The text above contains:")
-	fmt.Println(hCount, "Topics or sub topics")
-	fmt.Println(pCount, "positive points")
-	fmt.Println(nCount, "negative points")
+	fmt.Println(s.Topics(), "Topics or sub topics")
+	fmt.Println(s.Counts[ast.Positive], "positive points")
+	fmt.Println(s.Counts[ast.Negative], "negative points")
+	fmt.Println(s.Words, "words")
 }
@@ -0,0 +1,276 @@
+// Package html renders parsed notes as HTML.
+package html
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+)
+
+// Style is the default stylesheet for the classes used by rendered notes.
+const Style = `.dw-key-phrase { background-color: #fff3a0; }
+.dw-positive { color: #1a7f37; text-decoration: underline; }
+.dw-negative { color: #cf222e; text-decoration: underline wavy; }
+.dw-artifact { font-style: italic; color: #6639ba; }
+.dw-tag { color: #0969da; }
+.dw-broken { color: #cf222e; text-decoration: line-through; }
+`
+
+// Render renders 'notes' as a complete HTML document. The title of the
+// document is the first topic within the notes.
+func Render(notes ast.Notes) string {
+	return Document(Title(notes), Fragment(notes))
+}
+
+// Document wraps the HTML 'body' in a complete HTML document.
+func Document(title, body string) string {
+	sb := &strings.Builder{}
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	sb.WriteString(`<meta charset="utf-8">` + "\n")
+	sb.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	sb.WriteString("<style>\n" + Style + "</style>\n")
+	sb.WriteString("</head>\n<body>\n")
+	sb.WriteString(body)
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+// Title returns the text of the first topic within 'notes' or "Notes" if
+// there isn't one.
+func Title(notes ast.Notes) string {
+	for _, n := range notes {
+		if n.Type() == ast.Topic {
+			if s := strings.TrimSpace(n.Text()); s != "" {
+				return s
+			}
+		}
+	}
+	return "Notes"
+}
+
+// Options configures how notes are rendered.
+type Options struct {
+	// WikiHref returns the URL a wiki link points to, or false if the link is
+	// broken and should be rendered as text. If nil, DefaultWikiHref is used.
+	WikiHref func(ast.WikiLinkNode) (string, bool)
+
+	// DataLines adds a 'data-line' attribute holding the source line number,
+	// starting from one, to each heading, paragraph, and list item.
+	DataLines bool
+}
+
+// DefaultWikiHref returns the URL of a wiki link assuming each note is
+// rendered to a file named after the slug of the note with a '.html'
+// extension and topics are anchored by their slugs.
+func DefaultWikiHref(l ast.WikiLinkNode) (string, bool) {
+	href := ast.Slug(l.Note) + ".html"
+	if l.Topic != "" {
+		href += "#" + ast.Slug(l.Topic)
+	}
+	return href, true
+}
+
+// Fragment renders 'notes' as HTML without the surrounding document.
+func Fragment(notes ast.Notes) string {
+	return FragmentWith(notes, Options{})
+}
+
+// FragmentWith renders 'notes' as HTML, configured by 'opts', without the
+// surrounding document.
+func FragmentWith(notes ast.Notes, opts Options) string {
+	if opts.WikiHref == nil {
+		opts.WikiHref = DefaultWikiHref
+	}
+
+	r := &renderer{sb: &strings.Builder{}, opts: opts, anchors: ast.Anchors{}}
+	for _, b := range block.Build(notes) {
+		r.writeBlock(b)
+	}
+	return r.sb.String()
+}
+
+// Inline renders a sequence of phrase nodes as inline HTML.
+func Inline(ns []ast.Node) string {
+	r := &renderer{sb: &strings.Builder{}, opts: Options{WikiHref: DefaultWikiHref}}
+	r.writeNodes(ns)
+	return r.sb.String()
+}
+
+type renderer struct {
+	sb      *strings.Builder
+	opts    Options
+	anchors ast.Anchors
+}
+
+func (r *renderer) writeBlock(b block.Block) {
+	switch v := b.(type) {
+	case block.Heading:
+		tag := "h" + strconv.Itoa(headingLevel(v.Level))
+		id := r.anchors.Next(ast.MakeTextLine(v.Nodes...).Text())
+		r.sb.WriteString("<" + tag + ` id="` + id + `"` + r.dataLine(v.Line) + ">")
+		r.writeNodes(v.Nodes)
+		r.sb.WriteString("</" + tag + ">\n")
+
+	case block.Para:
+		r.sb.WriteString("<p" + r.dataLine(v.Line) + ">")
+		r.writeNodes(v.Nodes)
+		r.sb.WriteString("</p>\n")
+
+	case *block.List:
+		r.writeList(v)
+	}
+}
+
+func (r *renderer) writeList(l *block.List) {
+	tag := "ul"
+	if l.Ordered {
+		tag = "ol"
+	}
+
+	r.sb.WriteString("<" + tag)
+	if l.Ordered && len(l.Items) > 0 && l.Items[0].Num != 1 {
+		r.sb.WriteString(` start="` + strconv.Itoa(l.Items[0].Num) + `"`)
+	}
+	r.sb.WriteString(">\n")
+
+	for i, item := range l.Items {
+		r.sb.WriteString("<li" + r.dataLine(item.Line))
+		if l.Ordered && i > 0 && item.Start > 0 {
+			r.sb.WriteString(` value="` + strconv.Itoa(item.Num) + `"`)
+		}
+		r.sb.WriteString(">")
+		r.writeNodes(item.Nodes)
+		if len(item.Lists) > 0 {
+			r.sb.WriteString("\n")
+		}
+		for _, sub := range item.Lists {
+			r.writeList(sub)
+		}
+		r.sb.WriteString("</li>\n")
+	}
+
+	r.sb.WriteString("</" + tag + ">\n")
+}
+
+func (r *renderer) dataLine(line int) string {
+	if !r.opts.DataLines {
+		return ""
+	}
+	return ` data-line="` + strconv.Itoa(line) + `"`
+}
+
+func (r *renderer) writeNodes(ns []ast.Node) {
+	for _, n := range ns {
+		r.writeNode(n)
+	}
+}
+
+func (r *renderer) writeNode(n ast.Node) {
+
+	writeGroup := func(open string, n ast.Node, close string) {
+		r.sb.WriteString(open)
+		if p, ok := n.(ast.Parent); ok {
+			r.writeNodes(p.Nodes())
+		} else {
+			r.sb.WriteString(html.EscapeString(n.Text()))
+		}
+		r.sb.WriteString(close)
+	}
+
+	switch n.Type() {
+	case ast.KeyPhrase:
+		writeGroup(`<mark class="dw-key-phrase">`, n, "</mark>")
+	case ast.Positive:
+		writeGroup(`<span class="dw-positive">`, n, "</span>")
+	case ast.Negative:
+		writeGroup(`<span class="dw-negative">`, n, "</span>")
+	case ast.Strong:
+		writeGroup("<strong>", n, "</strong>")
+	case ast.Quote:
+		writeGroup("<q>", n, "</q>")
+	case ast.Artifact:
+		writeGroup(`<span class="dw-artifact">`, n, "</span>")
+	case ast.Snippet:
+		writeGroup("<code>", n, "</code>")
+	case ast.Link:
+		r.writeLink(n)
+	case ast.WikiLink:
+		r.writeWikiLink(n)
+	case ast.Tag:
+		writeGroup(`<span class="dw-tag">#`, n, "</span>")
+	default:
+		writeGroup("", n, "")
+	}
+}
+
+func (r *renderer) writeLink(n ast.Node) {
+	text := html.EscapeString(n.Text())
+
+	l, ok := n.(ast.LinkNode)
+	if !ok || !SafeURL(l.URL) {
+		r.sb.WriteString(text)
+		return
+	}
+
+	r.sb.WriteString(`<a href="` + html.EscapeString(l.URL) + `">`)
+	r.sb.WriteString(text)
+	r.sb.WriteString("</a>")
+}
+
+func (r *renderer) writeWikiLink(n ast.Node) {
+	text := html.EscapeString(n.Text())
+
+	l, ok := n.(ast.WikiLinkNode)
+	if !ok {
+		r.sb.WriteString(text)
+		return
+	}
+
+	href, ok := r.opts.WikiHref(l)
+	if !ok || !SafeURL(href) {
+		r.sb.WriteString(`<span class="dw-wiki-link dw-broken">` + text + "</span>")
+		return
+	}
+
+	r.sb.WriteString(`<a class="dw-wiki-link" href="` + html.EscapeString(href) + `">`)
+	r.sb.WriteString(text)
+	r.sb.WriteString("</a>")
+}
+
+// SafeURL returns true if 'url' is safe to use as a hyperlink, i.e. it is
+// relative or uses the http, https, ftp, or mailto scheme. URLs using other
+// schemes, such as 'javascript:', are considered unsafe.
+func SafeURL(url string) bool {
+
+	// Browsers ignore whitespace and control characters within schemes
+	s := strings.Map(func(ru rune) rune {
+		if ru <= ' ' || ru == 0x7F {
+			return -1
+		}
+		return ru
+	}, url)
+
+	i := strings.IndexAny(s, ":/?#")
+	if i < 0 || s[i] != ':' {
+		return true // Relative URL
+	}
+
+	switch strings.ToLower(s[:i]) {
+	case "http", "https", "ftp", "mailto":
+		return true
+	}
+	return false
+}
+
+func headingLevel(level int) int {
+	switch {
+	case level < 1:
+		return 1
+	case level > 6:
+		return 6
+	}
+	return level
+}
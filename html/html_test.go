@@ -0,0 +1,102 @@
+package html
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fragment(s string) string {
+	return Fragment(parser.ParseAll(scanner.ScanAll(s)))
+}
+
+func TestFragment_1(t *testing.T) {
+
+	in := `# Cheese <3
+Cheese is +very tasty+ but **smelly**
+! Curdling
+!! Souring
+! Ripening
+!5 Eating`
+
+	exp := `<h1 id="cheese-3">Cheese &lt;3</h1>
+<p>Cheese is <span class="dw-positive">very tasty</span> but <mark class="dw-key-phrase">smelly</mark></p>
+<ol>
+<li>Curdling
+<ol>
+<li>Souring</li>
+</ol>
+</li>
+<li>Ripening</li>
+<li value="5">Eating</li>
+</ol>
+`
+
+	require.Equal(t, exp, fragment(in))
+}
+
+func TestLinks_1(t *testing.T) {
+
+	in := `See https://a.org/?a=1&b=2 or [here](javascript:alert(1))`
+	exp := `<p>See <a href="https://a.org/?a=1&amp;b=2">https://a.org/?a=1&amp;b=2</a> or here</p>
+`
+
+	require.Equal(t, exp, fragment(in))
+}
+
+func TestWikiLinks_1(t *testing.T) {
+
+	in := `See [[Other Note#Early History]] and [[Missing]]`
+	exp := `<p>See <a class="dw-wiki-link" href="notes/other-note.html#early-history">Other Note#Early History</a> and <span class="dw-wiki-link dw-broken">Missing</span></p>
+`
+
+	opts := Options{
+		WikiHref: func(l ast.WikiLinkNode) (string, bool) {
+			if l.Note == "Missing" {
+				return "", false
+			}
+			href, _ := DefaultWikiHref(l)
+			return "notes/" + href, true
+		},
+	}
+
+	act := FragmentWith(parser.ParseAll(scanner.ScanAll(in)), opts)
+	require.Equal(t, exp, act)
+}
+
+func TestSafeURL_1(t *testing.T) {
+	require.True(t, SafeURL("https://example.com"))
+	require.True(t, SafeURL("mailto:me@example.com"))
+	require.True(t, SafeURL("notes/cheese.html#history"))
+	require.True(t, SafeURL("/a:b"))
+	require.False(t, SafeURL("javascript:alert(1)"))
+	require.False(t, SafeURL("JavaScript:alert(1)"))
+	require.False(t, SafeURL(" java\tscript:alert(1)"))
+	require.False(t, SafeURL("data:text/html,<b>"))
+	require.False(t, SafeURL("vbscript:x"))
+}
+
+func TestDataLines_1(t *testing.T) {
+	in := "# Cheese\n\nMilk\n. Rennet"
+	exp := `<h1 id="cheese" data-line="1">Cheese</h1>` + "\n" +
+		`<p data-line="3">Milk</p>` + "\n" +
+		"<ul>\n" + `<li data-line="4">Rennet</li>` + "\n</ul>\n"
+
+	act := FragmentWith(parser.ParseAll(scanner.ScanAll(in)), Options{DataLines: true})
+	require.Equal(t, exp, act)
+}
+
+func TestHeadingIDs_1(t *testing.T) {
+	in := "# Cheese\n## Types\n## Types\n## ?!\n## Types"
+	exp := `<h1 id="cheese">Cheese</h1>` + "\n" +
+		`<h2 id="types">Types</h2>` + "\n" +
+		`<h2 id="types-1">Types</h2>` + "\n" +
+		`<h2 id="topic">?!</h2>` + "\n" +
+		`<h2 id="types-2">Types</h2>` + "\n"
+
+	require.Equal(t, exp, fragment(in))
+}
@@ -0,0 +1,55 @@
+// Package ziptest provides helpers for testing the zip based document formats,
+// i.e. DOCX, ODT, and EPUB.
+package ziptest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Unzip returns the names of the files within the zip archive 'b', in order,
+// and their contents by name.
+func Unzip(t *testing.T, b []byte) ([]string, map[string]string) {
+	r, e := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.Nil(t, e)
+
+	names := []string{}
+	parts := map[string]string{}
+	for _, f := range r.File {
+		rc, e := f.Open()
+		require.Nil(t, e)
+		data, e := ioutil.ReadAll(rc)
+		require.Nil(t, e)
+		rc.Close()
+		names = append(names, f.Name)
+		parts[f.Name] = string(data)
+	}
+	return names, parts
+}
+
+// Stored returns true if the first file within the zip archive 'b' is stored
+// without compression, as the 'mimetype' file of ODT and EPUB must be.
+func Stored(t *testing.T, b []byte) bool {
+	r, e := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.Nil(t, e)
+	return len(r.File) > 0 && r.File[0].Method == zip.Store
+}
+
+// WellFormed fails the test if the file 'name', holding 's', is not well
+// formed XML.
+func WellFormed(t *testing.T, name, s string) {
+	d := xml.NewDecoder(bytes.NewReader([]byte(s)))
+	for {
+		_, e := d.Token()
+		if e == io.EOF {
+			return
+		}
+		require.Nil(t, e, name)
+	}
+}
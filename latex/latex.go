@@ -0,0 +1,366 @@
+// Package latex renders parsed notes as LaTeX.
+//
+// Topics become sectioning commands, '\section' for top level topics,
+// '\subsection' for the next level, and so on. Lists become 'itemize' and
+// 'enumerate' environments. LaTeX has no equivalent for some Daft Wullie
+// phrases so the following conventions are used:
+//   - key phrases, positive, negative, and artifact phrases are wrapped in
+//     macros that can be configured, see Options
+//   - snippets become '\verb' where LaTeX allows it and '\texttt' elsewhere,
+//     e.g. within headings and other macros
+//   - quotes are wrapped in typographic double quotes
+//   - links with unsafe schemes, see html.SafeURL, are rendered as text
+//   - wiki links are rendered as text since they point outside the document
+//
+// LaTeX allows lists to be nested at most four deep so deeper lists are
+// flattened into the fourth level.
+package latex
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+)
+
+// Default macro names used when Options leaves them empty.
+const (
+	DefaultKeyPhrase = `\dwkey`
+	DefaultPositive  = `\dwpos`
+	DefaultNegative  = `\dwneg`
+	DefaultArtifact  = `\dwartifact`
+)
+
+// maxNesting is the deepest LaTeX allows lists to be nested.
+const maxNesting = 4
+
+var macroPattern = regexp.MustCompile(`^\\[A-Za-z]+$`)
+
+// Options configures the macros phrases are wrapped in. Each is the name of
+// a macro taking one argument, including the leading backslash, e.g.
+// '\emph'. Empty or invalid names are replaced by the defaults.
+//
+// Document defines each macro with '\providecommand' so existing macros,
+// such as '\emph', keep their meaning.
+type Options struct {
+	KeyPhrase string
+	Positive  string
+	Negative  string
+	Artifact  string
+}
+
+func (opts Options) resolve() Options {
+	pick := func(name, def string) string {
+		if macroPattern.MatchString(name) {
+			return name
+		}
+		return def
+	}
+	return Options{
+		KeyPhrase: pick(opts.KeyPhrase, DefaultKeyPhrase),
+		Positive:  pick(opts.Positive, DefaultPositive),
+		Negative:  pick(opts.Negative, DefaultNegative),
+		Artifact:  pick(opts.Artifact, DefaultArtifact),
+	}
+}
+
+// Render renders 'notes' as a complete LaTeX document using the default
+// macros. The title of the document is the first topic within the notes.
+func Render(notes ast.Notes) string {
+	return RenderWith(notes, Options{})
+}
+
+// RenderWith renders 'notes' as a complete LaTeX document using the macros
+// in 'opts'.
+func RenderWith(notes ast.Notes, opts Options) string {
+	return Document(html.Title(notes), FragmentWith(notes, opts), opts)
+}
+
+// Document wraps the LaTeX 'body' in a complete article document. The
+// preamble loads the packages the body relies on and defines the macros in
+// 'opts'. The 'title' is only set as the title of the PDF, the body is
+// expected to start with its own heading so no title is typeset.
+func Document(title, body string, opts Options) string {
+	opts = opts.resolve()
+
+	sb := &strings.Builder{}
+	sb.WriteString(`\documentclass{article}` + "\n")
+	sb.WriteString(`\usepackage[T1]{fontenc}` + "\n")
+	sb.WriteString(`\usepackage[utf8]{inputenc}` + "\n")
+	sb.WriteString(`\usepackage{xcolor}` + "\n")
+	sb.WriteString(`\usepackage[normalem]{ulem}` + "\n")
+	sb.WriteString(`\usepackage{hyperref}` + "\n")
+	sb.WriteString(`\hypersetup{pdftitle={` + Escape(title) + "}}\n")
+	sb.WriteString("\n")
+	sb.WriteString(`\providecommand{` + opts.KeyPhrase + `}[1]{\colorbox{yellow!40}{#1}}` + "\n")
+	sb.WriteString(`\providecommand{` + opts.Positive + `}[1]{\textcolor{green!50!black}{\uline{#1}}}` + "\n")
+	sb.WriteString(`\providecommand{` + opts.Negative + `}[1]{\textcolor{red!70!black}{\uwave{#1}}}` + "\n")
+	sb.WriteString(`\providecommand{` + opts.Artifact + `}[1]{\textcolor{violet}{\textit{#1}}}` + "\n")
+	sb.WriteString("\n")
+	sb.WriteString(`\begin{document}` + "\n\n")
+	sb.WriteString(body)
+	sb.WriteString(`\end{document}` + "\n")
+	return sb.String()
+}
+
+// Fragment renders 'notes' as LaTeX, using the default macros, without the
+// surrounding document.
+func Fragment(notes ast.Notes) string {
+	return FragmentWith(notes, Options{})
+}
+
+// FragmentWith renders 'notes' as LaTeX, using the macros in 'opts',
+// without the surrounding document.
+func FragmentWith(notes ast.Notes, opts Options) string {
+	r := &renderer{sb: &strings.Builder{}, opts: opts.resolve()}
+	for _, b := range block.Build(notes) {
+		if _, ok := b.(block.Break); ok {
+			continue
+		}
+		r.writeBlock(b)
+		r.sb.WriteString("\n")
+	}
+	return r.sb.String()
+}
+
+// Inline renders a sequence of phrase nodes as inline LaTeX using the
+// default macros. Snippets are always rendered with '\texttt' so the result
+// may be used within the arguments of other macros.
+func Inline(ns []ast.Node) string {
+	r := &renderer{sb: &strings.Builder{}, opts: Options{}.resolve(), args: 1}
+	r.writeNodes(ns)
+	return r.sb.String()
+}
+
+type renderer struct {
+	sb    *strings.Builder
+	opts  Options
+	args  int // Depth of macro arguments being written
+	lists int // Depth of list environments
+	enums int // Depth of enumerate environments
+}
+
+func (r *renderer) writeBlock(b block.Block) {
+	switch v := b.(type) {
+	case block.Heading:
+		r.sb.WriteString(`\` + sectioning(v.Level) + "{")
+		r.args++
+		r.writeNodes(v.Nodes)
+		r.args--
+		r.sb.WriteString("}\n")
+
+	case block.Para:
+		if len(v.Nodes) == 0 {
+			return
+		}
+		r.writeNodes(v.Nodes)
+		r.sb.WriteString("\n")
+
+	case *block.List:
+		r.writeList(v)
+	}
+}
+
+func (r *renderer) writeList(l *block.List) {
+	if r.lists >= maxNesting {
+		r.writeItems(l)
+		return
+	}
+
+	env := "itemize"
+	if l.Ordered {
+		env = "enumerate"
+		r.enums++
+	}
+
+	r.lists++
+	r.sb.WriteString(`\begin{` + env + "}\n")
+	r.writeItems(l)
+	r.sb.WriteString(`\end{` + env + "}\n")
+	r.lists--
+
+	if l.Ordered {
+		r.enums--
+	}
+}
+
+func (r *renderer) writeItems(l *block.List) {
+	for i, item := range l.Items {
+		if l.Ordered && r.enums > 0 && (i == 0 && item.Num != 1 || i > 0 && item.Start > 0) {
+			r.sb.WriteString(`\setcounter{` + enumCounter(r.enums) + "}{")
+			r.sb.WriteString(strconv.Itoa(item.Num-1) + "}\n")
+		}
+
+		r.sb.WriteString(`\item`)
+		if len(item.Nodes) > 0 {
+			r.sb.WriteString(" ")
+			if s := item.Nodes[0]; s.Type() == ast.Text && strings.HasPrefix(s.Text(), "[") {
+				r.sb.WriteString("{}") // Not an optional argument
+			}
+			r.writeNodes(item.Nodes)
+		}
+		r.sb.WriteString("\n")
+
+		for _, sub := range item.Lists {
+			r.writeList(sub)
+		}
+	}
+}
+
+func (r *renderer) writeNodes(ns []ast.Node) {
+	for _, n := range ns {
+		r.writeNode(n)
+	}
+}
+
+func (r *renderer) writeNode(n ast.Node) {
+
+	writeGroup := func(open string, n ast.Node, close string) {
+		r.sb.WriteString(open)
+		if p, ok := n.(ast.Parent); ok {
+			r.writeNodes(p.Nodes())
+		} else {
+			r.sb.WriteString(Escape(n.Text()))
+		}
+		r.sb.WriteString(close)
+	}
+
+	writeMacro := func(macro string, n ast.Node) {
+		r.args++
+		writeGroup(macro+"{", n, "}")
+		r.args--
+	}
+
+	switch n.Type() {
+	case ast.KeyPhrase:
+		writeMacro(r.opts.KeyPhrase, n)
+	case ast.Positive:
+		writeMacro(r.opts.Positive, n)
+	case ast.Negative:
+		writeMacro(r.opts.Negative, n)
+	case ast.Strong:
+		writeMacro(`\textbf`, n)
+	case ast.Quote:
+		writeGroup("``", n, "''")
+	case ast.Artifact:
+		writeMacro(r.opts.Artifact, n)
+	case ast.Snippet:
+		r.writeSnippet(n.Text())
+	case ast.Link:
+		r.writeLink(n)
+	case ast.Tag:
+		r.sb.WriteString(Escape("#" + n.Text()))
+	default:
+		writeGroup("", n, "")
+	}
+}
+
+// writeSnippet writes 's' verbatim using '\verb' unless it is within the
+// argument of a macro, where '\verb' is not allowed, or there is no
+// delimiter absent from 's'. '\texttt' is used instead.
+func (r *renderer) writeSnippet(s string) {
+	if r.args == 0 && !strings.ContainsAny(s, "\r\n") {
+		if d := verbDelim(s); d != 0 {
+			r.sb.WriteString(`\verb` + string(d) + s + string(d))
+			return
+		}
+	}
+	r.sb.WriteString(`\texttt{` + Escape(s) + "}")
+}
+
+func (r *renderer) writeLink(n ast.Node) {
+	l, ok := n.(ast.LinkNode)
+	switch {
+	case !ok, !html.SafeURL(l.URL):
+		r.sb.WriteString(Escape(n.Text()))
+	case l.Label == "":
+		r.sb.WriteString(`\url{` + escapeURL(l.URL) + "}")
+	default:
+		r.args++
+		r.sb.WriteString(`\href{` + escapeURL(l.URL) + "}{" + Escape(l.Label) + "}")
+		r.args--
+	}
+}
+
+// Escape escapes characters within 's' that have special meaning in LaTeX
+// so they are typeset literally. Character pairs that fonts would join into
+// ligatures, such as '--' and '“', are separated.
+func Escape(s string) string {
+	sb := strings.Builder{}
+	var prev rune
+	for _, ru := range s {
+		if isLigature(prev, ru) {
+			sb.WriteString("{}")
+		}
+		prev = ru
+
+		switch ru {
+		case '\\':
+			sb.WriteString(`\textbackslash{}`)
+		case '^':
+			sb.WriteString(`\textasciicircum{}`)
+		case '~':
+			sb.WriteString(`\textasciitilde{}`)
+		case '{', '}', '$', '&', '#', '_', '%':
+			sb.WriteRune('\\')
+			sb.WriteRune(ru)
+		default:
+			sb.WriteRune(ru)
+		}
+	}
+	return sb.String()
+}
+
+func isLigature(a, b rune) bool {
+	switch string([]rune{a, b}) {
+	case "--", "``", "''", "<<", ">>", ",,", "!`", "?`":
+		return true
+	}
+	return false
+}
+
+// escapeURL escapes a URL for use within '\url' and '\href'. Braces and
+// backslashes are percent encoded since they cannot be escaped.
+func escapeURL(url string) string {
+	return strings.NewReplacer(
+		`\`, `\%5C`,
+		"{", `\%7B`,
+		"}", `\%7D`,
+		"%", `\%`,
+		"#", `\#`,
+	).Replace(url)
+}
+
+// verbDelim returns a delimiter for '\verb' that does not appear within 's'
+// or zero if there isn't one.
+func verbDelim(s string) rune {
+	for _, d := range "|!+=/:;@" {
+		if !strings.ContainsRune(s, d) {
+			return d
+		}
+	}
+	return 0
+}
+
+// enumCounter returns the name of the counter of enumerate environments
+// nested to 'depth', e.g. 'enumii'.
+func enumCounter(depth int) string {
+	return "enum" + []string{"i", "ii", "iii", "iv"}[depth-1]
+}
+
+func sectioning(level int) string {
+	switch {
+	case level <= 1:
+		return "section"
+	case level == 2:
+		return "subsection"
+	case level == 3:
+		return "subsubsection"
+	case level == 4:
+		return "paragraph"
+	}
+	return "subparagraph"
+}
@@ -0,0 +1,101 @@
+package latex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFragment_1(t *testing.T) {
+
+	in := `# Cheese & Wine
+## Types_of ~brie~
+. Chedder from $Somerset
+.. Mild *and* mature
+.. ` + "`a|b`" + `
+-Smelly- but +tasty+ and **ripe ` + "`{x}`" + `**
+See [the shop](https://a.org/c%20d#e), not [this](javascript:x)
+
+!3 Curdling
+! Ripening`
+
+	exp := `\section{Cheese \& Wine}
+
+\subsection{Types\_of \textasciitilde{}brie\textasciitilde{}}
+
+\begin{itemize}
+\item Chedder from \dwartifact{Somerset}
+\begin{itemize}
+\item Mild \textbf{and} mature
+\item \verb!a|b!
+\end{itemize}
+\end{itemize}
+
+\dwneg{Smelly} but \dwpos{tasty} and \dwkey{ripe \texttt{\{x\}}}
+
+See \href{https://a.org/c\%20d\#e}{the shop}, not this
+
+\begin{enumerate}
+\setcounter{enumi}{2}
+\item Curdling
+\item Ripening
+\end{enumerate}
+
+`
+
+	act := Fragment(parser.ParseAll(scanner.ScanAll(in)))
+	require.Equal(t, exp, act)
+}
+
+func TestFragmentWith_1(t *testing.T) {
+
+	in := "+good+ -bad- **key** $thing"
+	opts := Options{
+		KeyPhrase: `\emph`,
+		Positive:  `\good`,
+		Negative:  "not a macro",
+	}
+
+	exp := `\good{good} \dwneg{bad} \emph{key} \dwartifact{thing}` + "\n\n"
+	act := FragmentWith(parser.ParseAll(scanner.ScanAll(in)), opts)
+	require.Equal(t, exp, act)
+}
+
+func TestRender_1(t *testing.T) {
+	act := RenderWith(parser.ParseAll(scanner.ScanAll("# 100% Cheese")), Options{KeyPhrase: `\emph`})
+
+	require.True(t, strings.HasPrefix(act, `\documentclass{article}`))
+	require.Contains(t, act, `\providecommand{\emph}[1]`)
+	require.Contains(t, act, `\providecommand{\dwpos}[1]`)
+	require.Contains(t, act, `\hypersetup{pdftitle={100\% Cheese}}`)
+	require.NotContains(t, act, `\title`)
+	require.Contains(t, act, `\begin{document}`+"\n\n"+`\section{100\% Cheese}`)
+	require.True(t, strings.HasSuffix(act, `\end{document}`+"\n"))
+}
+
+func TestNesting_1(t *testing.T) {
+
+	in := `. 1
+.. 2
+... 3
+.... 4
+..... 5`
+
+	act := Fragment(parser.ParseAll(scanner.ScanAll(in)))
+	require.Equal(t, 4, strings.Count(act, `\begin{itemize}`))
+	require.Equal(t, 4, strings.Count(act, `\end{itemize}`))
+	require.Contains(t, act, `\item 4`+"\n"+`\item 5`+"\n")
+}
+
+func TestEscape_1(t *testing.T) {
+	require.Equal(t,
+		`\textbackslash{}\{x\} \$1 \& \#2 \_3 50\% \textasciitilde{}\textasciicircum{}`,
+		Escape(`\{x} $1 & #2 _3 50% ~^`))
+	require.Equal(t, "a-{}-b `{}` '{}'", Escape("a--b `` ''"))
+	require.Equal(t, `\texttt{\{\}}`, Inline([]ast.Node{ast.MakeSnippet("{}")}))
+}
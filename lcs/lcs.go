@@ -0,0 +1,100 @@
+// Package lcs finds the longest common subsequence of two sequences of lines.
+//
+// Lines common to the start and end of both sequences are matched first, the
+// rest are matched using Hirschberg's algorithm, so memory use is linear in
+// the number of lines rather than proportional to the product of the two.
+package lcs
+
+// Match returns, for each line of 'a', the index of the line of 'b' it is
+// matched with in their longest common subsequence, or -1 if it has none.
+func Match(a, b []string) []int {
+	r := make([]int, len(a))
+	for i := range r {
+		r[i] = -1
+	}
+
+	lo := 0
+	for lo < len(a) && lo < len(b) && a[lo] == b[lo] {
+		r[lo] = lo
+		lo++
+	}
+
+	hiA, hiB := len(a), len(b)
+	for hiA > lo && hiB > lo && a[hiA-1] == b[hiB-1] {
+		hiA, hiB = hiA-1, hiB-1
+		r[hiA] = hiB
+	}
+
+	match(a[:hiA], b[:hiB], lo, lo, r)
+	return r
+}
+
+// match matches the lines of a[i:] with those of b[j:], recording them in
+// 'r', by splitting 'a' in half and finding where 'b' is best split to match
+// each half.
+func match(a, b []string, i, j int, r []int) {
+	switch {
+	case i >= len(a) || j >= len(b):
+		return
+	case len(a)-i == 1:
+		for k := j; k < len(b); k++ {
+			if a[i] == b[k] {
+				r[i] = k
+				return
+			}
+		}
+		return
+	}
+
+	mid := i + (len(a)-i)/2
+	before := lengths(a[i:mid], b[j:], false)
+	after := lengths(a[mid:], b[j:], true)
+
+	split, best := j, -1
+	for k := range before {
+		if n := before[k] + after[k]; n > best {
+			split, best = j+k, n
+		}
+	}
+
+	match(a[:mid], b[:split], i, j, r)
+	match(a, b, mid, split, r)
+}
+
+// lengths returns the length of the longest common subsequence of 'a' and
+// each prefix of 'b', indexed by the length of the prefix. If 'suffixes' is
+// true it is of 'a' and each suffix of 'b', indexed by where it starts.
+func lengths(a, b []string, suffixes bool) []int {
+	m := len(b)
+	prev, curr := make([]int, m+1), make([]int, m+1)
+
+	for x := range a {
+		if suffixes {
+			ax := a[len(a)-1-x]
+			for y := m - 1; y >= 0; y-- {
+				curr[y] = max(prev[y], curr[y+1])
+				if ax == b[y] {
+					curr[y] = prev[y+1] + 1
+				}
+			}
+		} else {
+			ax := a[x]
+			for y := 1; y <= m; y++ {
+				curr[y] = max(prev[y], curr[y-1])
+				if ax == b[y-1] {
+					curr[y] = prev[y-1] + 1
+				}
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,62 @@
+package lcs
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch_1(t *testing.T) {
+	a := strings.Split("a b c d e f", " ")
+	b := strings.Split("a x c d y f z", " ")
+	require.Equal(t, []int{0, -1, 2, 3, -1, 5}, Match(a, b))
+	require.Equal(t, []int{-1, -1}, Match([]string{"a", "b"}, nil))
+	require.Empty(t, Match(nil, b))
+}
+
+func TestMatch_2(t *testing.T) {
+
+	// Matches form a common subsequence as long as any other
+	longest := func(a, b []string) int {
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				}
+			}
+		}
+		return lcs[0][0]
+	}
+
+	random := func(r *rand.Rand) []string {
+		s := make([]string, r.Intn(12))
+		for i := range s {
+			s[i] = strconv.Itoa(r.Intn(4))
+		}
+		return s
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 2000; n++ {
+		a, b := random(r), random(r)
+		m := Match(a, b)
+
+		count, last := 0, -1
+		for i, j := range m {
+			if j < 0 {
+				continue
+			}
+			require.True(t, j > last && a[i] == b[j], "%v %v: %v", a, b, m)
+			count, last = count+1, j
+		}
+		require.Equal(t, longest(a, b), count, "%v %v: %v", a, b, m)
+	}
+}
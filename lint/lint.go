@@ -0,0 +1,186 @@
+// Package lint checks notes for likely mistakes, e.g. a phrase opened within
+// a phrase of the same type or a placeholder left unfilled. Unclosed phrases
+// are not reported since running a phrase to the end of the line is normal.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/merge"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+	"github.com/PaulioRandall/daft-wullie-go/templates"
+)
+
+// Severity is how serious an issue is.
+type Severity int
+
+const (
+	// Warning is an issue that is probably a mistake but still valid.
+	Warning Severity = iota
+	// Error is an issue that should be fixed before the note is used.
+	Error
+)
+
+type (
+	// Issue is a problem found on a line of a note.
+	Issue struct {
+		Line     int // Starting from one
+		Severity Severity
+		Rule     string
+		Message  string
+	}
+
+	// Options control which rules are applied.
+	Options struct {
+		// Template allows placeholders, e.g. '{{title}}', and reports malformed
+		// ones instead.
+		Template bool
+	}
+)
+
+// String returns the severity's human readable string representation.
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// String returns the issue in the form 'line: severity: message (rule)'.
+func (i Issue) String() string {
+	return fmt.Sprintf("%d: %s: %s (%s)", i.Line, i.Severity, i.Message, i.Rule)
+}
+
+// HasErrors returns true if any of 'issues' is an Error.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// phraseNames are the names of the phrase node types used within messages.
+var phraseNames = map[ast.NodeType]string{
+	ast.KeyPhrase: "key phrase",
+	ast.Positive:  "positive phrase",
+	ast.Negative:  "negative phrase",
+	ast.Strong:    "strong phrase",
+	ast.Quote:     "quote",
+	ast.Artifact:  "artifact",
+	ast.Snippet:   "snippet",
+}
+
+// Check checks the note text 'src' and returns the issues found in order of
+// line.
+func Check(src string, opts Options) []Issue {
+	c := &checker{opts: opts}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	notes := parser.ParseAll(scanner.ScanAllFlanking(src))
+
+	for i, n := range notes {
+		c.line = i + 1
+		if merge.IsMarker(lines[i]) {
+			c.report(Error, "conflict", "merge conflict marker")
+			continue
+		}
+		c.checkPlaceholders(lines[i])
+		c.checkStructure(n)
+		for _, sub := range children(n) {
+			c.checkPhrases(sub, "", map[ast.NodeType]bool{})
+		}
+	}
+	return c.issues
+}
+
+type checker struct {
+	opts   Options
+	line   int
+	topic  int // Level of the last topic
+	depth  int // Depth of the last list item, zero if the last line wasn't one
+	issues []Issue
+}
+
+func (c *checker) report(sev Severity, rule, format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{
+		Line:     c.line,
+		Severity: sev,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) checkPlaceholders(line string) {
+	switch {
+	case c.opts.Template && templates.Malformed(line):
+		c.report(Error, "placeholder", "malformed placeholder, expected '{{name}}'")
+	case !c.opts.Template && templates.Contains(line):
+		c.report(Error, "placeholder", "unfilled placeholder")
+	}
+}
+
+func (c *checker) checkStructure(n ast.Node) {
+	level := ast.Level(n)
+
+	switch n.Type() {
+	case ast.Topic, ast.SubTopic:
+		if strings.TrimSpace(n.Text()) == "" {
+			c.report(Warning, "empty-topic", "topic has no title")
+		}
+		if level > c.topic+1 {
+			c.report(Warning, "topic-level", "topic level %d follows level %d", level, c.topic)
+		}
+		c.topic = level
+		c.depth = 0
+
+	case ast.BulPoint, ast.SubBulPoint, ast.NumPoint, ast.SubNumPoint:
+		if level > c.depth+1 {
+			c.report(Warning, "list-depth", "list item depth %d follows depth %d", level, c.depth)
+		}
+		c.depth = level
+
+	default:
+		c.depth = 0
+	}
+}
+
+// checkPhrases checks the phrase 'n' and its descendants. 'open' holds the
+// types of the phrases enclosing 'n' and 'parent' the type of its parent.
+func (c *checker) checkPhrases(n ast.Node, parent ast.NodeType, open map[ast.NodeType]bool) {
+	name, ok := phraseNames[n.Type()]
+	if !ok {
+		return
+	}
+
+	if strings.TrimSpace(n.Text()) == "" {
+		c.report(Warning, "empty-phrase", "empty %s", name)
+	}
+
+	if open[n.Type()] {
+		// E.g. '"a +b" c' where the second quote, meant to close the first,
+		// opens a new quote because the positive phrase is still open
+		c.report(Warning, "nested-phrase", "%s opened within a %s, close the %s first",
+			name, name, phraseNames[parent])
+	}
+
+	inner := make(map[ast.NodeType]bool, len(open)+1)
+	for k := range open {
+		inner[k] = true
+	}
+	inner[n.Type()] = true
+
+	for _, sub := range children(n) {
+		c.checkPhrases(sub, n.Type(), inner)
+	}
+}
+
+func children(n ast.Node) []ast.Node {
+	if p, ok := n.(ast.Parent); ok {
+		return p.Nodes()
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func rules(issues []Issue) []string {
+	r := []string{}
+	for _, i := range issues {
+		r = append(r, i.Rule)
+	}
+	return r
+}
+
+func TestCheck_1(t *testing.T) {
+	src := "# Cheese\n. +tasty and -smelly\n\"Quote with +positive+ point\" $By Me"
+	require.Empty(t, Check(src, Options{}))
+}
+
+func TestCheck_2(t *testing.T) {
+	src := "#\n### Deep\n. milk\n... eggs"
+	issues := Check(src, Options{})
+
+	require.Equal(t, []string{"empty-topic", "topic-level", "list-depth"}, rules(issues))
+	require.Equal(t, 4, issues[2].Line)
+	require.False(t, HasErrors(issues))
+}
+
+func TestCheck_3(t *testing.T) {
+	issues := Check(`"a +b" c`+"\n**key** \"\"", Options{})
+	require.Equal(t, []string{"nested-phrase", "empty-phrase"}, rules(issues))
+	require.Equal(t, "1: warning: quote opened within a quote, close the positive phrase first (nested-phrase)",
+		issues[0].String())
+
+	issues = Check("+a *b +c", Options{})
+	require.Equal(t, "1: warning: positive phrase opened within a positive phrase, close the strong phrase first (nested-phrase)",
+		issues[0].String())
+}
+
+func TestCheck_4(t *testing.T) {
+	src := "# {{title}}\n{{date\n{{bad name}}"
+
+	issues := Check(src, Options{})
+	require.Equal(t, []string{"placeholder"}, rules(issues))
+	require.True(t, HasErrors(issues))
+
+	issues = Check(src, Options{Template: true})
+	require.Equal(t, 2, len(issues))
+	require.Equal(t, 2, issues[0].Line)
+	require.Equal(t, 3, issues[1].Line)
+}
+
+func TestCheck_5(t *testing.T) {
+	src := "# A\n<<<<<<< ours\n. a\n=======\n. b\n>>>>>>> theirs"
+	issues := Check(src, Options{})
+
+	require.Equal(t, []string{"conflict", "conflict", "conflict"}, rules(issues))
+	require.Equal(t, 6, issues[2].Line)
+}
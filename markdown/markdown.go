@@ -0,0 +1,217 @@
+// Package markdown renders parsed notes as CommonMark Markdown.
+//
+// Markdown has no equivalent for some Daft Wullie phrases so the following
+// conventions are used:
+//   - key phrases become '<mark>' elements
+//   - positive, negative, and artifact phrases become '<span>' elements with
+//     the classes 'dw-positive', 'dw-negative', and 'dw-artifact', as used by
+//     the html package
+//   - quotes are wrapped in double quotes
+//   - links with unsafe schemes, see html.SafeURL, are rendered as text
+package markdown
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+)
+
+var entities = strings.NewReplacer(
+	"<", "&lt;",
+	">", "&gt;",
+	"&", "&amp;",
+	`"`, "&quot;",
+)
+
+// Render renders 'notes' as Markdown.
+func Render(notes ast.Notes) string {
+	sb := &strings.Builder{}
+	for _, b := range block.Build(notes) {
+		if _, ok := b.(block.Break); ok {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		writeBlock(sb, b)
+	}
+
+	return sb.String()
+}
+
+func writeBlock(sb *strings.Builder, b block.Block) {
+	switch v := b.(type) {
+	case block.Heading:
+		sb.WriteString(strings.Repeat("#", headingLevel(v.Level)) + " ")
+		sb.WriteString(Inline(v.Nodes))
+		sb.WriteString("\n")
+
+	case block.Para:
+		sb.WriteString(escapeLineStart(Inline(v.Nodes)))
+		sb.WriteString("\n")
+
+	case *block.List:
+		writeList(sb, v, "")
+	}
+}
+
+func writeList(sb *strings.Builder, l *block.List, indent string) {
+	for _, item := range l.Items {
+		marker := "- "
+		if l.Ordered {
+			marker = strconv.Itoa(item.Num) + ". "
+		}
+
+		sb.WriteString(indent + marker)
+		sb.WriteString(escapeLineStart(Inline(item.Nodes)))
+		sb.WriteString("\n")
+
+		for _, sub := range item.Lists {
+			writeList(sb, sub, indent+strings.Repeat(" ", len(marker)))
+		}
+	}
+}
+
+// Inline renders a sequence of phrase nodes as inline Markdown.
+func Inline(ns []ast.Node) string {
+	sb := &strings.Builder{}
+	for _, n := range ns {
+		writeNode(sb, n)
+	}
+	return sb.String()
+}
+
+func writeNode(sb *strings.Builder, n ast.Node) {
+
+	writeGroup := func(open string, n ast.Node, close string) {
+		sb.WriteString(open)
+		if p, ok := n.(ast.Parent); ok {
+			for _, c := range p.Nodes() {
+				writeNode(sb, c)
+			}
+		} else {
+			sb.WriteString(Escape(n.Text()))
+		}
+		sb.WriteString(close)
+	}
+
+	switch n.Type() {
+	case ast.KeyPhrase:
+		writeGroup("<mark>", n, "</mark>")
+	case ast.Positive:
+		writeGroup(`<span class="dw-positive">`, n, "</span>")
+	case ast.Negative:
+		writeGroup(`<span class="dw-negative">`, n, "</span>")
+	case ast.Strong:
+		writeGroup("**", n, "**")
+	case ast.Quote:
+		writeGroup(`"`, n, `"`)
+	case ast.Artifact:
+		writeGroup(`<span class="dw-artifact">`, n, "</span>")
+	case ast.Snippet:
+		sb.WriteString(codeSpan(n.Text()))
+	case ast.Link:
+		writeLink(sb, n)
+	case ast.WikiLink:
+		writeWikiLink(sb, n)
+	case ast.Tag:
+		sb.WriteString(Escape("#" + n.Text()))
+	default:
+		writeGroup("", n, "")
+	}
+}
+
+func writeLink(sb *strings.Builder, n ast.Node) {
+	l, ok := n.(ast.LinkNode)
+	switch {
+	case !ok, !html.SafeURL(l.URL):
+		sb.WriteString(Escape(n.Text()))
+	case l.Label == "":
+		url := strings.NewReplacer("<", "%3C", ">", "%3E", " ", "%20").Replace(l.URL)
+		sb.WriteString("<" + url + ">")
+	default:
+		url := strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(l.URL)
+		sb.WriteString("[" + Escape(l.Label) + "](" + url + ")")
+	}
+}
+
+// writeWikiLink writes a wiki link as a link to the Markdown file named after
+// the slug of the note.
+func writeWikiLink(sb *strings.Builder, n ast.Node) {
+	l, ok := n.(ast.WikiLinkNode)
+	if !ok {
+		sb.WriteString(Escape(n.Text()))
+		return
+	}
+
+	url := ast.Slug(l.Note) + ".md"
+	if l.Topic != "" {
+		url += "#" + ast.Slug(l.Topic)
+	}
+	sb.WriteString("[" + Escape(l.Text()) + "](" + url + ")")
+}
+
+// Escape escapes characters within 's' that have special meaning in
+// Markdown. HTML special characters are replaced by entities.
+func Escape(s string) string {
+	sb := strings.Builder{}
+	for _, ru := range s {
+		switch {
+		case strings.ContainsRune(`<>&"`, ru):
+			sb.WriteString(entities.Replace(string(ru)))
+		case strings.ContainsRune("\\`*_[]#|~!", ru):
+			sb.WriteRune('\\')
+			sb.WriteRune(ru)
+		default:
+			sb.WriteRune(ru)
+		}
+	}
+	return sb.String()
+}
+
+// escapeLineStart escapes the start of a paragraph or list item that would
+// otherwise be read as a list item or block quote.
+func escapeLineStart(s string) string {
+	if s == "" {
+		return s
+	}
+
+	if strings.ContainsRune("-+=>", rune(s[0])) {
+		return `\` + s
+	}
+
+	i := 0
+	for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+	}
+	if i > 0 && i < len(s) && (s[i] == '.' || s[i] == ')') {
+		return s[:i] + `\` + s[i:]
+	}
+
+	return s
+}
+
+// codeSpan returns 's' as a code span using enough backticks to contain any
+// backticks within it.
+func codeSpan(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+func headingLevel(level int) int {
+	switch {
+	case level < 1:
+		return 1
+	case level > 6:
+		return 6
+	}
+	return level
+}
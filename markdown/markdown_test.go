@@ -0,0 +1,69 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_1(t *testing.T) {
+
+	in := `# Cheese
+## Types
+. Chedder from $Somerset
+.. Mild *and* mature
+-Smelly- but https://a.org/cheese
+1. is not a list, [nor](javascript:x) is this
+
+!3 Curdling
+! Ripening`
+
+	exp := `# Cheese
+
+## Types
+
+- Chedder from <span class="dw-artifact">Somerset</span>
+  - Mild **and** mature
+
+<span class="dw-negative">Smelly</span> but <https://a.org/cheese>
+
+1\. is not a list, nor is this
+
+3. Curdling
+4. Ripening
+`
+
+	act := Render(parser.ParseAll(scanner.ScanAll(in)))
+	require.Equal(t, exp, act)
+}
+
+func TestEscape_1(t *testing.T) {
+	require.Equal(t, `2 \* 3 &lt; \_x\_ \[y\]`, Escape(`2 * 3 < _x_ [y]`))
+	require.Equal(t, "``a`b``", codeSpan("a`b"))
+}
+
+func TestRender_2(t *testing.T) {
+
+	// List items that start like a list item or block quote are escaped
+	in := `. \- foo
+. \1. bar
+! \+ baz`
+
+	exp := `- \- foo
+- 1\. bar
+
+1. \+ baz
+`
+
+	act := Render(parser.ParseAll(scanner.ScanAll(in)))
+	require.Equal(t, exp, act)
+}
+
+func TestRender_3(t *testing.T) {
+	in := ast.Notes{ast.MakeTextLine(ast.MakeLink("https://a.org/<x> y", ""))}
+	require.Equal(t, "<https://a.org/%3Cx%3E%20y>\n", Render(in))
+}
@@ -0,0 +1,110 @@
+package merge
+
+import (
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/lcs"
+)
+
+// merger merges lines, counting the conflicts it marks.
+type merger struct {
+	opts      Options
+	conflicts int
+}
+
+// lines merges the changes made to 'base' by 'ours' and 'theirs'. Lines that
+// only differ by whitespace are considered the same and ours are preferred.
+func (m *merger) lines(base, ours, theirs []string) []string {
+	mo := match(base, ours)
+	mt := match(base, theirs)
+
+	r := []string{}
+	i, io, it := 0, 0, 0
+
+	for {
+		// Find the next base line kept by both sides
+		k := i
+		for k < len(base) && (mo[k] < 0 || mt[k] < 0) {
+			k++
+		}
+
+		eo, et := len(ours), len(theirs)
+		if k < len(base) {
+			eo, et = mo[k], mt[k]
+		}
+
+		r = append(r, m.chunk(base[i:k], ours[io:eo], theirs[it:et])...)
+		if k == len(base) {
+			return r
+		}
+
+		r = append(r, ours[eo])
+		i, io, it = k+1, eo+1, et+1
+	}
+}
+
+// chunk merges a chunk of lines that differ between the versions.
+func (m *merger) chunk(base, ours, theirs []string) []string {
+	switch {
+	case equal(ours, base):
+		return theirs
+	case equal(theirs, base), equal(ours, theirs):
+		return ours
+	case len(base) == 0:
+		return union(ours, theirs) // Both sides added lines
+	}
+
+	m.conflicts++
+	r := []string{MarkerOurs + " " + m.opts.OursLabel}
+	r = append(r, ours...)
+	r = append(r, MarkerSep)
+	r = append(r, theirs...)
+	return append(r, MarkerTheirs+" "+m.opts.TheirsLabel)
+}
+
+// union returns the lines of 'a' followed by the lines of 'b' that are not in
+// 'a', so a line added by both sides appears once.
+func union(a, b []string) []string {
+	seen := map[string]bool{}
+	for _, s := range a {
+		seen[key(s)] = true
+	}
+
+	r := append([]string{}, a...)
+	for _, s := range b {
+		if !seen[key(s)] {
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+// match returns, for each line of 'a', the index of the matching line of
+// 'b', or -1 if it has none, using their longest common subsequence.
+func match(a, b []string) []int {
+	keys := func(ss []string) []string {
+		r := make([]string, len(ss))
+		for i, s := range ss {
+			r[i] = key(s)
+		}
+		return r
+	}
+	return lcs.Match(keys(a), keys(b))
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if key(a[i]) != key(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// key returns the form of a line used for comparison.
+func key(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
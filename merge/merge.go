@@ -0,0 +1,311 @@
+// Package merge performs three-way merges of notes. Notes are merged topic
+// by topic, then line by line within each topic, so changes made to
+// different topics never conflict even if the topics were reordered. Topics
+// are ordered as the side that reordered them.
+//
+// Conflicts that can be resolved safely are, e.g. the same line added by
+// both sides appears once and lines added by both sides at the same place
+// are all kept. Other conflicts are marked with the same lines git uses:
+//
+//	<<<<<<< ours
+//	. Rennet is +cheap+
+//	=======
+//	. Rennet is -expensive-
+//	>>>>>>> theirs
+//
+// The linter reports conflict markers as errors so they are not forgotten.
+package merge
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+// Conflict markers, each starts a line and may be followed by a label.
+const (
+	MarkerOurs   = "<<<<<<<"
+	MarkerBase   = "|||||||"
+	MarkerSep    = "======="
+	MarkerTheirs = ">>>>>>>"
+)
+
+type (
+	// Options configures a merge.
+	Options struct {
+		OursLabel   string // Label following the ours marker, default "ours"
+		TheirsLabel string // Label following the theirs marker, default "theirs"
+	}
+
+	// Result is the result of a merge.
+	Result struct {
+		Text      string
+		Conflicts int
+	}
+
+	// section is a topic and the lines beneath it, up to the next topic of
+	// any level. The first section of a note holds the lines before the first
+	// topic and has no heading.
+	section struct {
+		key     string // Level and slug of the topic, numbered if repeated
+		heading string
+		lines   []string
+		gap     int  // Trailing empty lines, separating it from the next section
+		last    bool // True if it was the last section of its note
+	}
+)
+
+// IsMarker returns true if the line 's' is a conflict marker.
+func IsMarker(s string) bool {
+	for _, m := range []string{MarkerOurs, MarkerBase, MarkerSep, MarkerTheirs} {
+		if s == m || strings.HasPrefix(s, m+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge merges the changes made to the note 'base' by 'ours' and 'theirs'.
+func Merge(base, ours, theirs string, opts Options) Result {
+	if opts.OursLabel == "" {
+		opts.OursLabel = "ours"
+	}
+	if opts.TheirsLabel == "" {
+		opts.TheirsLabel = "theirs"
+	}
+
+	m := &merger{opts: opts}
+	b, o, t := sections(base), sections(ours), sections(theirs)
+
+	r := []string{}
+	merged := m.sections(b, o, t)
+	for i, s := range merged {
+		if s.heading != "" {
+			r = append(r, s.heading)
+		}
+		r = append(r, s.lines...)
+
+		gap := s.gap
+		if gap == 0 && s.last {
+			gap = 1 // Was the last section but may no longer be
+		}
+		for ; gap > 0 && i+1 < len(merged); gap-- {
+			r = append(r, "")
+		}
+	}
+
+	text := strings.Join(r, "\n")
+	if strings.HasSuffix(ours, "\n") && text != "" {
+		text += "\n"
+	}
+	return Result{Text: text, Conflicts: m.conflicts}
+}
+
+// sections merges the sections of each version. Sections are ordered as the
+// side that reordered them, or ours if neither did, with sections only the
+// other side has placed after the section preceding them in that side. If
+// both sides reordered the sections differently they are ordered as ours and
+// the order is marked as a conflict.
+func (m *merger) sections(base, ours, theirs []section) []section {
+	bi, oi, ti := index(base), index(ours), index(theirs)
+	first, second := ours, theirs
+
+	kb, ko, kt := shared(base, oi, ti), shared(ours, bi, ti), shared(theirs, bi, oi)
+	oursMoved, theirsMoved := !equal(ko, kb), !equal(kt, kb)
+	conflict := oursMoved && theirsMoved && !equal(ko, kt)
+	if theirsMoved && !oursMoved {
+		first, second = theirs, ours
+	}
+
+	order := make([]string, 0, len(ours)+len(theirs))
+	placed := map[string]bool{}
+	for _, s := range first {
+		order = append(order, s.key)
+		placed[s.key] = true
+	}
+
+	for i, s := range second {
+		if placed[s.key] {
+			continue
+		}
+		at := 0
+		for j := i - 1; j >= 0; j-- {
+			if k := position(order, second[j].key); k >= 0 {
+				at = k + 1
+				break
+			}
+		}
+		order = append(order[:at], append([]string{s.key}, order[at:]...)...)
+		placed[s.key] = true
+	}
+
+	r := []section{}
+	for _, k := range order {
+		if s, ok := m.section(k, get(base, bi, k), get(ours, oi, k), get(theirs, ti, k)); ok {
+			r = append(r, s)
+		}
+	}
+
+	if conflict {
+		r = m.orderConflict(r, ours, theirs)
+	}
+	return r
+}
+
+// shared returns the keys of the sections in 'ss' that are also in the
+// versions indexed by 'a' and 'b', in order.
+func shared(ss []section, a, b map[string]int) []string {
+	r := []string{}
+	for _, s := range ss {
+		_, inA := a[s.key]
+		_, inB := b[s.key]
+		if inA && inB {
+			r = append(r, s.key)
+		}
+	}
+	return r
+}
+
+// orderConflict marks the order of the merged sections 'r' as conflicting
+// by listing the headings of 'ours' and 'theirs', in order, at the start of
+// the note.
+func (m *merger) orderConflict(r, ours, theirs []section) []section {
+	headings := func(ss []section) []string {
+		r := []string{}
+		for _, s := range ss {
+			if s.heading != "" {
+				r = append(r, s.heading)
+			}
+		}
+		return r
+	}
+
+	m.conflicts++
+	lines := []string{MarkerOurs + " " + m.opts.OursLabel}
+	lines = append(lines, headings(ours)...)
+	lines = append(lines, MarkerSep)
+	lines = append(lines, headings(theirs)...)
+	lines = append(lines, MarkerTheirs+" "+m.opts.TheirsLabel)
+
+	if len(r) == 0 || r[0].key != "" {
+		return append([]section{{lines: lines}}, r...)
+	}
+	r[0].lines = append(lines, r[0].lines...)
+	return r
+}
+
+// section merges a single section, any of which may be nil if the version
+// doesn't have it. False is returned if the section was removed.
+func (m *merger) section(key string, base, ours, theirs *section) (section, bool) {
+	empty := &section{key: key}
+	switch {
+	case ours == nil && theirs == nil:
+		return section{}, false // Removed by both
+	case base != nil && ours == nil && equalSection(base, theirs):
+		return section{}, false // Removed by us
+	case base != nil && theirs == nil && equalSection(base, ours):
+		return section{}, false // Removed by them
+	}
+
+	if base == nil {
+		base = empty
+	}
+
+	heading := pick(base, ours, theirs)
+	from := ours
+	if from == nil {
+		from = theirs
+	}
+
+	switch {
+	case ours == nil:
+		// Removed by us but changed by them
+		return section{key, heading, m.chunk(base.lines, nil, theirs.lines), from.gap, from.last}, true
+	case theirs == nil:
+		return section{key, heading, m.chunk(base.lines, ours.lines, nil), from.gap, from.last}, true
+	}
+
+	return section{key, heading, m.lines(base.lines, ours.lines, theirs.lines), from.gap, from.last}, true
+}
+
+// pick returns the heading of the section, preferring a changed one.
+func pick(base, ours, theirs *section) string {
+	switch {
+	case ours == nil:
+		return theirs.heading
+	case theirs == nil, ours.heading != base.heading:
+		return ours.heading
+	}
+	return theirs.heading
+}
+
+func equalSection(a, b *section) bool {
+	return a.heading == b.heading && equal(a.lines, b.lines)
+}
+
+// sections splits the note 's' into its sections. Trailing empty lines are
+// not considered part of a section's lines so moving a topic to the end of a
+// note does not change it.
+func sections(s string) []section {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if s == "" {
+		return []section{{}}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	notes := notebook.Parse(strings.Join(lines, "\n"))
+
+	r := []section{{}}
+	seen := map[string]int{}
+
+	for i, n := range notes {
+		if n.Type() != ast.Topic && n.Type() != ast.SubTopic {
+			last := &r[len(r)-1]
+			last.lines = append(last.lines, lines[i])
+			continue
+		}
+
+		k := strconv.Itoa(ast.Level(n)) + ":" + ast.Slug(n.Text())
+		seen[k]++
+		if seen[k] > 1 {
+			k += ":" + strconv.Itoa(seen[k])
+		}
+		r = append(r, section{key: k, heading: lines[i]})
+	}
+
+	r[len(r)-1].last = true
+	for i := range r {
+		s := &r[i]
+		for len(s.lines) > 0 && strings.TrimSpace(s.lines[len(s.lines)-1]) == "" {
+			s.lines = s.lines[:len(s.lines)-1]
+			s.gap++
+		}
+	}
+	return r
+}
+
+func index(ss []section) map[string]int {
+	r := make(map[string]int, len(ss))
+	for i, s := range ss {
+		r[s.key] = i
+	}
+	return r
+}
+
+func get(ss []section, idx map[string]int, key string) *section {
+	if i, ok := idx[key]; ok {
+		return &ss[i]
+	}
+	return nil
+}
+
+func position(order []string, key string) int {
+	for i, k := range order {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
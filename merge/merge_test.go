@@ -0,0 +1,125 @@
+package merge
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const base = `# Cheese
+. Milk
+. Rennet
+
+# Curds
+. Cut
+. Stir
+`
+
+func TestMerge_1(t *testing.T) {
+	ours := "# Cheese\n. Milk\n. Salt\n. Rennet\n\n# Curds\n. Cut\n. Stir\n"
+	theirs := "# Cheese\n. Milk\n. Rennet\n\n# Curds\n. Cut\n. Stir\n. Drain\n"
+	exp := "# Cheese\n. Milk\n. Salt\n. Rennet\n\n# Curds\n. Cut\n. Stir\n. Drain\n"
+
+	r := Merge(base, ours, theirs, Options{})
+	require.Equal(t, exp, r.Text)
+	require.Equal(t, 0, r.Conflicts)
+}
+
+func TestMerge_2(t *testing.T) {
+	// Same bullet added by both, plus different bullets at the same place
+	ours := "# Cheese\n. Milk\n. Rennet\n. Salt\n\n# Curds\n. Cut\n. Stir\n"
+	theirs := "# Cheese\n. Milk\n. Rennet\n.  Salt\n. Cloth\n\n# Curds\n. Cut\n. Stir\n"
+	exp := "# Cheese\n. Milk\n. Rennet\n. Salt\n. Cloth\n\n# Curds\n. Cut\n. Stir\n"
+
+	r := Merge(base, ours, theirs, Options{})
+	require.Equal(t, exp, r.Text)
+	require.Equal(t, 0, r.Conflicts)
+}
+
+func TestMerge_3(t *testing.T) {
+	// Topics reordered by us and changed by them
+	ours := "# Curds\n. Cut\n. Stir\n# Cheese\n. Milk\n. Rennet\n\n"
+	theirs := "# Cheese\n. Milk\n. Rennet\n\n# Curds\n. Cut\n. Stir\n\n# Whey\n. Ricotta\n"
+	exp := "# Curds\n. Cut\n. Stir\n# Whey\n. Ricotta\n\n# Cheese\n. Milk\n. Rennet\n"
+
+	r := Merge(base, ours, theirs, Options{})
+	require.Equal(t, exp, r.Text)
+	require.Equal(t, 0, r.Conflicts)
+}
+
+func TestMerge_4(t *testing.T) {
+	ours := "# Cheese\n. Milk\n. +Animal+ rennet\n\n# Curds\n. Cut\n. Stir\n"
+	theirs := "# Cheese\n. Milk\n. -Vegetable- rennet\n\n# Curds\n. Cut\n"
+	exp := "# Cheese\n. Milk\n" +
+		"<<<<<<< HEAD\n. +Animal+ rennet\n=======\n. -Vegetable- rennet\n>>>>>>> feature\n" +
+		"\n# Curds\n. Cut\n"
+
+	r := Merge(base, ours, theirs, Options{OursLabel: "HEAD", TheirsLabel: "feature"})
+	require.Equal(t, exp, r.Text)
+	require.Equal(t, 1, r.Conflicts)
+}
+
+func TestMerge_5(t *testing.T) {
+	// Topic removed by us but changed by them
+	ours := "# Cheese\n. Milk\n. Rennet\n"
+	theirs := base + ". Drain\n"
+
+	r := Merge(base, ours, theirs, Options{})
+	require.Equal(t, 1, r.Conflicts)
+	require.Contains(t, r.Text, "# Curds\n<<<<<<< ours\n=======\n. Cut\n. Stir\n. Drain\n>>>>>>> theirs\n")
+
+	// Removed by us and unchanged by them
+	r = Merge(base, ours, base, Options{})
+	require.Equal(t, ours, r.Text)
+}
+
+func TestIsMarker_1(t *testing.T) {
+	require.True(t, IsMarker("<<<<<<< HEAD"))
+	require.True(t, IsMarker("======="))
+	require.False(t, IsMarker("========"))
+	require.False(t, IsMarker("<<<<<<<<"))
+}
+
+func TestMerge_6(t *testing.T) {
+	// Topics moved to the end of the note by them and changed by us
+	ours := "# Cheese\n. Milk\n. Rennet\n. Salt\n\n# Curds\n. Cut\n. Stir\n"
+	theirs := "# Curds\n. Cut\n. Stir\n\n# Cheese\n. Milk\n. Rennet\n"
+	exp := "# Curds\n. Cut\n. Stir\n\n# Cheese\n. Milk\n. Rennet\n. Salt\n"
+
+	r := Merge(base, ours, theirs, Options{})
+	require.Equal(t, exp, r.Text)
+	require.Equal(t, 0, r.Conflicts)
+
+	// Topics reordered differently by both
+	base := "# A\n# B\n# C\n"
+	exp = "<<<<<<< ours\n# B\n# A\n# C\n=======\n# A\n# C\n# B\n>>>>>>> theirs\n# B\n# A\n# C\n"
+
+	r = Merge(base, "# B\n# A\n# C\n", "# A\n# C\n# B\n", Options{})
+	require.Equal(t, exp, r.Text)
+	require.Equal(t, 1, r.Conflicts)
+
+	// Topics reordered the same by both
+	r = Merge(base, "# C\n# A\n# B\n", "# C\n# A\n. a\n# B\n", Options{})
+	require.Equal(t, "# C\n# A\n. a\n# B\n", r.Text)
+	require.Equal(t, 0, r.Conflicts)
+
+	require.Equal(t, "# A\n. a\n# B\n", Merge("# A\n# B\n", "# A\n. a\n# B\n", "# A\n# B\n", Options{}).Text)
+}
+
+func TestMerge_7(t *testing.T) {
+	// Long topics are merged without a table of every pair of lines
+	lines := make([]string, 50000)
+	for i := range lines {
+		lines[i] = ". line " + strconv.Itoa(i)
+	}
+	base := "# Big\n" + strings.Join(lines, "\n") + "\n"
+	ours := base + ". ours\n"
+	lines[25000] = ". theirs"
+	theirs := "# Big\n" + strings.Join(lines, "\n") + "\n"
+
+	r := Merge(base, ours, theirs, Options{})
+	require.Equal(t, 0, r.Conflicts)
+	require.Equal(t, theirs+". ours\n", r.Text)
+}
@@ -0,0 +1,75 @@
+package mindmap
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DOT returns the mind map rooted at 'root' as a Graphviz DOT graph laid out
+// from left to right.
+func DOT(root *Node) string {
+	sb := &strings.Builder{}
+	sb.WriteString("digraph mindmap {\n")
+	sb.WriteString("  graph [rankdir=LR];\n")
+	sb.WriteString(`  node [shape=box, style="rounded,filled", fillcolor="#ffffff", color="#57606a", fontname="Helvetica"];` + "\n")
+	sb.WriteString(`  edge [color="#57606a", arrowhead=none];` + "\n")
+
+	id := 0
+	var walk func(n *Node) string
+	walk = func(n *Node) string {
+		name := "n" + strconv.Itoa(id)
+		id++
+
+		sb.WriteString("  " + name + " [label=" + dotString(n.Label) + dotNodeAttrs(n) + "];\n")
+		for _, c := range n.Children {
+			child := walk(c)
+			sb.WriteString("  " + name + " -> " + child + dotEdgeAttrs(c) + ";\n")
+		}
+		return name
+	}
+	walk(root)
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func dotNodeAttrs(n *Node) string {
+	attrs := ""
+	switch n.Kind {
+	case Root:
+		attrs += `, shape=ellipse, fillcolor="#ddf4ff", fontsize=18`
+	case Topic:
+		attrs += `, fillcolor="#f6f8fa", fontsize=16`
+	case SubTopic:
+		attrs += `, fillcolor="#f6f8fa"`
+	case KeyPhrase:
+		attrs += `, shape=note, style=filled, fillcolor="#fff3a0"`
+	case Artifact:
+		attrs += `, shape=note, style=filled, fontcolor="#6639ba", fontname="Helvetica-Oblique"`
+	}
+
+	switch n.Tone {
+	case Positive:
+		attrs += `, color="#1a7f37", fontcolor="#1a7f37", penwidth=2`
+	case Negative:
+		attrs += `, color="#cf222e", fontcolor="#cf222e", penwidth=2`
+	}
+	return attrs
+}
+
+func dotEdgeAttrs(n *Node) string {
+	switch n.Tone {
+	case Positive:
+		return ` [color="#1a7f37", penwidth=2]`
+	case Negative:
+		return ` [color="#cf222e", penwidth=2, style=dashed]`
+	}
+	return ""
+}
+
+// dotString quotes 's' as a DOT string.
+func dotString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
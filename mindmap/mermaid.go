@@ -0,0 +1,75 @@
+package mindmap
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Mermaid returns the mind map rooted at 'root' as a Mermaid 'mindmap'
+// diagram.
+//
+// Mermaid picks the colours of mind maps from its theme so positive and
+// negative nodes are given the classes 'dw-positive' and 'dw-negative' for
+// the page's stylesheet to colour. Their labels are also prefixed with '+'
+// and '-' so they still stand out where the classes aren't styled. Key
+// phrases are drawn as hexagons and artifacts as clouds.
+func Mermaid(root *Node) string {
+	sb := &strings.Builder{}
+	sb.WriteString("mindmap\n")
+
+	id := 0
+	var walk func(n *Node, depth int)
+	walk = func(n *Node, depth int) {
+		indent := strings.Repeat("  ", depth)
+		name := "n" + strconv.Itoa(id)
+		id++
+
+		open, close := mermaidShape(n.Kind)
+		sb.WriteString(indent + name + open + `"` + mermaidText(mermaidLabel(n)) + `"` + close + "\n")
+
+		switch n.Tone {
+		case Positive:
+			sb.WriteString(indent + ":::dw-positive\n")
+		case Negative:
+			sb.WriteString(indent + ":::dw-negative\n")
+		}
+
+		for _, c := range n.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(root, 1)
+
+	return sb.String()
+}
+
+func mermaidShape(k Kind) (string, string) {
+	switch k {
+	case Root:
+		return "((", "))"
+	case Topic, SubTopic:
+		return "(", ")"
+	case KeyPhrase:
+		return "{{", "}}"
+	case Artifact:
+		return ")", "("
+	default:
+		return "[", "]"
+	}
+}
+
+func mermaidLabel(n *Node) string {
+	switch n.Tone {
+	case Positive:
+		return "+ " + n.Label
+	case Negative:
+		return "- " + n.Label
+	}
+	return n.Label
+}
+
+// mermaidText escapes double quotes, which would end the label, as Mermaid
+// entity codes.
+func mermaidText(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,196 @@
+// Package mindmap builds a mind map from the hierarchy of parsed notes and
+// writes it as a Graphviz DOT graph or a Mermaid 'mindmap' diagram, ready to
+// be pasted into documentation and wikis.
+//
+// The root of the map is the note itself. Topics branch from the root, sub
+// topics from their topics, and list items from the topic above them, with
+// nested items branching from their parent item. Text lines are left out but
+// key phrases and artifacts, from any line, can optionally be added as leaves
+// of the node they appear within.
+//
+// Nodes containing only positive phrases are coloured green and those
+// containing only negative phrases red, along with the edges leading to them.
+package mindmap
+
+import (
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+)
+
+// Kind is the kind of a node within a mind map.
+type Kind int
+
+const (
+	Root Kind = iota
+	Topic
+	SubTopic
+	Item
+	KeyPhrase
+	Artifact
+)
+
+// Tone is whether a node is positive, negative, or neither.
+type Tone int
+
+const (
+	Neutral Tone = iota
+	Positive
+	Negative
+)
+
+// Node is a node within a mind map.
+type Node struct {
+	Kind     Kind
+	Tone     Tone
+	Label    string
+	Children []*Node
+}
+
+// Options configures how a mind map is built.
+type Options struct {
+	// Root is the label of the root node, "Notes" if empty.
+	Root string
+
+	// Leaves adds the key phrases and artifacts of each line as leaves.
+	Leaves bool
+}
+
+// Build builds a mind map of 'notes' returning its root node.
+func Build(notes ast.Notes, opts Options) *Node {
+	root := &Node{Kind: Root, Label: opts.Root}
+	if root.Label == "" {
+		root.Label = "Notes"
+	}
+
+	// Headings above the current line, the root being level zero.
+	stack := []*Node{root}
+	levels := []int{0}
+
+	for _, b := range block.Build(notes) {
+		top := stack[len(stack)-1]
+
+		switch v := b.(type) {
+		case block.Heading:
+			for levels[len(levels)-1] >= v.Level {
+				stack = stack[:len(stack)-1]
+				levels = levels[:len(levels)-1]
+			}
+
+			kind := SubTopic
+			if v.Level == 1 {
+				kind = Topic
+			}
+
+			n := newNode(kind, v.Nodes, opts)
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, n)
+			stack = append(stack, n)
+			levels = append(levels, v.Level)
+
+		case block.Para:
+			if opts.Leaves {
+				addLeaves(top, v.Nodes)
+			}
+
+		case *block.List:
+			addList(top, v, opts)
+		}
+	}
+
+	return root
+}
+
+func addList(parent *Node, l *block.List, opts Options) {
+	for _, item := range l.Items {
+		n := newNode(Item, item.Nodes, opts)
+		parent.Children = append(parent.Children, n)
+		for _, sub := range item.Lists {
+			addList(n, sub, opts)
+		}
+	}
+}
+
+func newNode(kind Kind, ns []ast.Node, opts Options) *Node {
+	n := &Node{
+		Kind:  kind,
+		Tone:  toneOf(ns),
+		Label: label(ns),
+	}
+	if opts.Leaves {
+		addLeaves(n, ns)
+	}
+	return n
+}
+
+// addLeaves adds the key phrases and artifacts within 'ns' to 'parent',
+// skipping any it already has.
+func addLeaves(parent *Node, ns []ast.Node) {
+	for _, n := range ns {
+		var kind Kind
+		switch n.Type() {
+		case ast.KeyPhrase:
+			kind = KeyPhrase
+		case ast.Artifact:
+			kind = Artifact
+		default:
+			if p, ok := n.(ast.Parent); ok {
+				addLeaves(parent, p.Nodes())
+			}
+			continue
+		}
+
+		leaf := &Node{Kind: kind, Tone: toneOf([]ast.Node{n}), Label: label([]ast.Node{n})}
+		if leaf.Label != "" && !hasChild(parent, leaf) {
+			parent.Children = append(parent.Children, leaf)
+		}
+		if p, ok := n.(ast.Parent); ok {
+			addLeaves(parent, p.Nodes())
+		}
+	}
+}
+
+func hasChild(parent, n *Node) bool {
+	for _, c := range parent.Children {
+		if c.Kind == n.Kind && c.Label == n.Label {
+			return true
+		}
+	}
+	return false
+}
+
+// toneOf returns Positive if 'ns' contain positive phrases but no negative
+// ones, Negative if the reverse, and Neutral otherwise.
+func toneOf(ns []ast.Node) Tone {
+	pos, neg := false, false
+
+	var walk func([]ast.Node)
+	walk = func(ns []ast.Node) {
+		for _, n := range ns {
+			switch n.Type() {
+			case ast.Positive:
+				pos = true
+			case ast.Negative:
+				neg = true
+			}
+			if p, ok := n.(ast.Parent); ok {
+				walk(p.Nodes())
+			}
+		}
+	}
+	walk(ns)
+
+	switch {
+	case pos && !neg:
+		return Positive
+	case neg && !pos:
+		return Negative
+	default:
+		return Neutral
+	}
+}
+
+func label(ns []ast.Node) string {
+	return strings.Join(strings.Fields(ast.MakeTextLine(ns...).Text()), " ")
+}
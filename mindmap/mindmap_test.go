@@ -0,0 +1,101 @@
+package mindmap
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+const in = `Intro with $cheese$
+# Cheese
+## Types
+. **Chedder** is +tasty+
+.. "Mild"
+! -Smelly-
+### Soft
+# Wine
+Pairs with **bread**`
+
+func build(leaves bool) *Node {
+	return Build(parser.ParseAll(scanner.ScanAll(in)), Options{Root: "Food", Leaves: leaves})
+}
+
+func TestBuild_1(t *testing.T) {
+	exp := &Node{Kind: Root, Label: "Food", Children: []*Node{
+		{Kind: Topic, Label: "Cheese", Children: []*Node{
+			{Kind: SubTopic, Label: "Types", Children: []*Node{
+				{Kind: Item, Tone: Positive, Label: "Chedder is tasty", Children: []*Node{
+					{Kind: Item, Label: "Mild"},
+				}},
+				{Kind: Item, Tone: Negative, Label: "Smelly"},
+				{Kind: SubTopic, Label: "Soft"},
+			}},
+		}},
+		{Kind: Topic, Label: "Wine"},
+	}}
+	require.Equal(t, exp, build(false))
+}
+
+func TestBuild_2(t *testing.T) {
+	root := build(true)
+	require.Equal(t, &Node{Kind: Artifact, Label: "cheese"}, root.Children[0])
+
+	chedder := root.Children[1].Children[0].Children[0]
+	require.Equal(t, &Node{Kind: KeyPhrase, Label: "Chedder"}, chedder.Children[0])
+
+	wine := root.Children[2]
+	require.Equal(t, []*Node{{Kind: KeyPhrase, Label: "bread"}}, wine.Children)
+}
+
+func TestDOT_1(t *testing.T) {
+	root := &Node{Kind: Root, Label: `Say "hi"`, Children: []*Node{
+		{Kind: Topic, Label: "Good", Tone: Positive},
+		{Kind: Item, Label: "Bad", Tone: Negative},
+		{Kind: KeyPhrase, Label: "Key"},
+	}}
+
+	exp := `digraph mindmap {
+  graph [rankdir=LR];
+  node [shape=box, style="rounded,filled", fillcolor="#ffffff", color="#57606a", fontname="Helvetica"];
+  edge [color="#57606a", arrowhead=none];
+  n0 [label="Say \"hi\"", shape=ellipse, fillcolor="#ddf4ff", fontsize=18];
+  n1 [label="Good", fillcolor="#f6f8fa", fontsize=16, color="#1a7f37", fontcolor="#1a7f37", penwidth=2];
+  n0 -> n1 [color="#1a7f37", penwidth=2];
+  n2 [label="Bad", color="#cf222e", fontcolor="#cf222e", penwidth=2];
+  n0 -> n2 [color="#cf222e", penwidth=2, style=dashed];
+  n3 [label="Key", shape=note, style=filled, fillcolor="#fff3a0"];
+  n0 -> n3;
+}
+`
+	require.Equal(t, exp, DOT(root))
+}
+
+func TestMermaid_1(t *testing.T) {
+	exp := `mindmap
+  n0(("Food"))
+    n1("Cheese")
+      n2("Types")
+        n3["+ Chedder is tasty"]
+        :::dw-positive
+          n4["Mild"]
+        n5["- Smelly"]
+        :::dw-negative
+        n6("Soft")
+    n7("Wine")
+`
+	require.Equal(t, exp, Mermaid(build(false)))
+
+	root := &Node{Kind: Root, Label: "R", Children: []*Node{
+		{Kind: KeyPhrase, Label: `a "b"`},
+		{Kind: Artifact, Label: "c"},
+	}}
+	exp = `mindmap
+  n0(("R"))
+    n1{{"a #quot;b#quot;"}}
+    n2)"c"(
+`
+	require.Equal(t, exp, Mermaid(root))
+}
@@ -0,0 +1,238 @@
+// Package minutes compiles meeting minutes from a meeting note, i.e. the
+// attendees, the topics discussed along with the key phrases, positives, and
+// negatives raised under each, and the action items.
+//
+// Sections are recognised by the slugs of their topics, see ast.Slug:
+//   - attendees are the artifacts within an 'Attendees', 'Participants', or
+//     'Present' section, e.g. '. $Alice$', or, if there is no such section,
+//     every artifact that is not a date
+//   - action items are the lines within an 'Actions', 'Action Items', 'To Do',
+//     or 'Next Steps' section along with any line tagged '#action' or '#todo'
+//   - every other section is a topic discussed
+package minutes
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/tags"
+)
+
+type (
+	// Minutes are the minutes of a meeting.
+	Minutes struct {
+		Title     string
+		Date      time.Time // Zero if the note has no date
+		Attendees []string
+		Topics    []Topic
+		Actions   []Action
+	}
+
+	// Topic is a section of the meeting note along with the phrases raised
+	// within it. Phrases are the phrase nodes as they appear in the note.
+	Topic struct {
+		Title      string
+		Line       int
+		KeyPhrases []ast.Node
+		Positives  []ast.Node
+		Negatives  []ast.Node
+	}
+
+	// Action is an action item.
+	Action struct {
+		Line  int
+		Topic string     // Title of the section it appears in, if any
+		Nodes []ast.Node // Content of the line without its list symbol
+	}
+)
+
+var (
+	attendeeSections = []string{"attendees", "attendance", "participants", "present"}
+	actionSections   = []string{"actions", "action-items", "to-do", "todo", "next-steps"}
+	actionTags       = []string{"action", "todo"}
+)
+
+// Raised returns the total number of phrases raised within the topic.
+func (t Topic) Raised() int {
+	return len(t.KeyPhrases) + len(t.Positives) + len(t.Negatives)
+}
+
+// Compile compiles the minutes of the meeting note 'notes'.
+func Compile(notes ast.Notes) Minutes {
+	m := Minutes{
+		Attendees: []string{},
+		Topics:    []Topic{},
+		Actions:   []Action{},
+	}
+
+	if dates := notebook.Dates(notes); len(dates) > 0 {
+		m.Date = dates[0]
+	}
+
+	var (
+		kind     = "general" // Kind of the current section
+		section  string      // Title of the current section
+		topic    *Topic
+		people   []string
+		artifact []string // Non-date artifacts in case there is no attendee section
+		tagged   = tags.Lines(notes)
+	)
+
+	for i, n := range notes {
+		line := i + 1
+
+		if n.Type() == ast.Topic || n.Type() == ast.SubTopic {
+			section = strings.TrimSpace(n.Text())
+			kind = sectionKind(section)
+
+			if m.Title == "" && ast.Level(n) == 1 {
+				m.Title = section
+				kind = "general"
+				section = ""
+			}
+
+			topic = nil
+			if kind == "" {
+				m.Topics = append(m.Topics, Topic{Title: section, Line: line})
+				topic = &m.Topics[len(m.Topics)-1]
+			}
+			continue
+		}
+
+		content := block.Trim(children(n))
+		if strings.TrimSpace(ast.MakeTextLine(content...).Text()) == "" {
+			continue
+		}
+
+		if kind == "actions" || hasAny(tagged[line], actionTags) {
+			m.Actions = append(m.Actions, Action{Line: line, Topic: section, Nodes: content})
+		}
+
+		if kind == "attendees" {
+			people = append(people, artifacts(n, false)...)
+			continue
+		}
+		artifact = append(artifact, artifacts(n, true)...)
+
+		if topic == nil && kind == "general" {
+			// Only lines under the title that raise something form a topic
+			general := Topic{Title: "General", Line: line}
+			if collect(&general, n); general.Raised() > 0 {
+				m.Topics = append(m.Topics, general)
+				topic = &m.Topics[len(m.Topics)-1]
+			}
+		} else if topic != nil {
+			collect(topic, n)
+		}
+	}
+
+	if people == nil {
+		people = artifact
+	}
+	m.Attendees = unique(people)
+	return m
+}
+
+// sectionKind returns the kind of section titled 'title', i.e. "attendees",
+// "actions", or "" for a topic.
+func sectionKind(title string) string {
+	slug := ast.Slug(title)
+	switch {
+	case hasAny([]string{slug}, attendeeSections):
+		return "attendees"
+	case hasAny([]string{slug}, actionSections):
+		return "actions"
+	}
+	return ""
+}
+
+// collect adds the phrases within 'n' to the topic 't'. Phrases within
+// artifacts and snippets belong to them so are not collected.
+func collect(t *Topic, n ast.Node) {
+	switch n.Type() {
+	case ast.Artifact, ast.Snippet:
+		return
+	case ast.KeyPhrase:
+		t.KeyPhrases = append(t.KeyPhrases, n)
+	case ast.Positive:
+		t.Positives = append(t.Positives, n)
+	case ast.Negative:
+		t.Negatives = append(t.Negatives, n)
+	}
+
+	for _, c := range children(n) {
+		collect(t, c)
+	}
+}
+
+// artifacts returns the text of the artifacts within 'n'. If 'namesOnly' is
+// true, artifacts that look like dates, times, or numbers are excluded.
+func artifacts(n ast.Node, namesOnly bool) []string {
+	r := []string{}
+
+	var walk func(ast.Node)
+	walk = func(n ast.Node) {
+		if n.Type() != ast.Artifact {
+			for _, c := range children(n) {
+				walk(c)
+			}
+			return
+		}
+
+		s := strings.TrimSpace(n.Text())
+		if s != "" && (!namesOnly || isName(s)) {
+			r = append(r, s)
+		}
+	}
+
+	walk(n)
+	return r
+}
+
+// isName returns true if 's' looks like the name of a person rather than a
+// date, time, or number.
+func isName(s string) bool {
+	if _, ok := notebook.ParseDate(strings.Fields(s)[0]); ok {
+		return false
+	}
+	for _, ru := range s {
+		if ru >= '0' && ru <= '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func unique(names []string) []string {
+	r := []string{}
+	seen := map[string]bool{}
+	for _, s := range names {
+		k := strings.ToLower(s)
+		if !seen[k] {
+			seen[k] = true
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+func hasAny(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func children(n ast.Node) []ast.Node {
+	if p, ok := n.(ast.Parent); ok {
+		return p.Nodes()
+	}
+	return []ast.Node{}
+}
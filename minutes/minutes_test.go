@@ -0,0 +1,101 @@
+package minutes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+const meeting = `# Cheese sync
+$2021-02-06$
+
+## Attendees
+. $Alice$
+. $Bob$ and $alice$
+
+## Rennet
+. **Animal rennet** is +cheap+
+. -Hard to source- #action
+
+## Curdling
+. Nothing new
+
+## Actions
+! Call the dairy
+!
+`
+
+func TestCompile_1(t *testing.T) {
+	m := Compile(notebook.Parse(meeting))
+
+	require.Equal(t, "Cheese sync", m.Title)
+	require.Equal(t, "2021-02-06", m.Date.Format("2006-01-02"))
+	require.Equal(t, []string{"Alice", "Bob"}, m.Attendees)
+
+	require.Equal(t, 2, len(m.Topics))
+	require.Equal(t, "Rennet", m.Topics[0].Title)
+	require.Equal(t, 8, m.Topics[0].Line)
+	require.Equal(t, 3, m.Topics[0].Raised())
+	require.Equal(t, "Animal rennet", m.Topics[0].KeyPhrases[0].Text())
+	require.Equal(t, 0, m.Topics[1].Raised())
+
+	require.Equal(t, 2, len(m.Actions))
+	require.Equal(t, "Rennet", m.Actions[0].Topic)
+	require.Equal(t, 16, m.Actions[1].Line)
+}
+
+func TestCompile_2(t *testing.T) {
+	m := Compile(notebook.Parse("# Sync\n$Alice$ said **cost** $2021-02-06$ $10:30$"))
+
+	require.Equal(t, []string{"Alice"}, m.Attendees)
+	require.Equal(t, 1, len(m.Topics))
+	require.Equal(t, "General", m.Topics[0].Title)
+}
+
+func TestCompile_3(t *testing.T) {
+	m := Compile(notebook.Parse("# Sync\n## Attendees\n. $Mary-Jane O'Neil$\n. $Anne-Marie-Smith$ and $Bob$\n. $Jo-Ann$ and $Lee-Ann$"))
+	require.Equal(t, []string{"Mary-Jane O'Neil", "Anne-Marie-Smith", "Bob", "Jo-Ann", "Lee-Ann"}, m.Attendees)
+
+	m = Compile(notebook.Parse("# Sync\n$Mary-Jane O'Neil$ said **cost**"))
+	require.Equal(t, []string{"Mary-Jane O'Neil"}, m.Attendees)
+}
+
+func TestPlain_1(t *testing.T) {
+	exp := `Minutes: Cheese sync
+Date: 2021-02-06
+
+Attendees
+  - Alice
+  - Bob
+
+Topics
+  Rennet
+    Key phrases
+      - Animal rennet
+    Positives
+      - cheap
+    Negatives
+      - Hard to source
+  Curdling
+    Nothing raised.
+
+Actions
+  - Hard to source #action
+  - Call the dairy
+`
+	require.Equal(t, exp, Plain(Compile(notebook.Parse(meeting))))
+}
+
+func TestMarkdown_1(t *testing.T) {
+	s := Markdown(Compile(notebook.Parse(meeting)))
+	require.Contains(t, s, "### Rennet\n\n**Key phrases**\n\n- Animal rennet\n")
+	require.Contains(t, s, "## Actions\n\n- [ ] <span class=\"dw-negative\">Hard to source</span>")
+}
+
+func TestHTML_1(t *testing.T) {
+	s := HTML(Compile(notebook.Parse(meeting)))
+	require.Contains(t, s, "<h4>Positives</h4>\n<ul>\n<li>cheap</li>\n</ul>\n")
+	require.Contains(t, s, `<li>Call the dairy</li>`)
+}
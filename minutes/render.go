@@ -0,0 +1,185 @@
+package minutes
+
+import (
+	gohtml "html"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+	"github.com/PaulioRandall/daft-wullie-go/markdown"
+)
+
+const dateLayout = "2006-01-02"
+
+// group is a named list of phrases within a topic.
+type group struct {
+	name  string
+	nodes []ast.Node
+}
+
+func groups(t Topic) []group {
+	return []group{
+		{"Key phrases", t.KeyPhrases},
+		{"Positives", t.Positives},
+		{"Negatives", t.Negatives},
+	}
+}
+
+// Markdown renders the minutes as Markdown.
+func Markdown(m Minutes) string {
+	sb := &strings.Builder{}
+	sb.WriteString("# Minutes: " + markdown.Escape(m.Title) + "\n")
+	if !m.Date.IsZero() {
+		sb.WriteString("\n**Date:** " + m.Date.Format(dateLayout) + "\n")
+	}
+
+	if len(m.Attendees) > 0 {
+		sb.WriteString("\n## Attendees\n\n")
+		for _, s := range m.Attendees {
+			sb.WriteString("- " + markdown.Escape(s) + "\n")
+		}
+	}
+
+	if len(m.Topics) > 0 {
+		sb.WriteString("\n## Topics\n")
+	}
+	for _, t := range m.Topics {
+		sb.WriteString("\n### " + markdown.Escape(t.Title) + "\n")
+		if t.Raised() == 0 {
+			sb.WriteString("\nNothing raised.\n")
+		}
+		for _, g := range groups(t) {
+			if len(g.nodes) == 0 {
+				continue
+			}
+			sb.WriteString("\n**" + g.name + "**\n\n")
+			for _, n := range g.nodes {
+				sb.WriteString("- " + markdown.Inline(children(n)) + "\n")
+			}
+		}
+	}
+
+	if len(m.Actions) > 0 {
+		sb.WriteString("\n## Actions\n\n")
+		for _, a := range m.Actions {
+			sb.WriteString("- [ ] " + markdown.Inline(a.Nodes) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// HTML renders the minutes as an HTML fragment, see html.Document to wrap it
+// in a complete document.
+func HTML(m Minutes) string {
+	sb := &strings.Builder{}
+	sb.WriteString("<h1>Minutes: " + gohtml.EscapeString(m.Title) + "</h1>\n")
+	if !m.Date.IsZero() {
+		sb.WriteString(`<p>Date: <time datetime="` + m.Date.Format(dateLayout) + `">`)
+		sb.WriteString(m.Date.Format(dateLayout) + "</time></p>\n")
+	}
+
+	if len(m.Attendees) > 0 {
+		sb.WriteString("<h2>Attendees</h2>\n<ul>\n")
+		for _, s := range m.Attendees {
+			sb.WriteString("<li>" + gohtml.EscapeString(s) + "</li>\n")
+		}
+		sb.WriteString("</ul>\n")
+	}
+
+	if len(m.Topics) > 0 {
+		sb.WriteString("<h2>Topics</h2>\n")
+	}
+	for _, t := range m.Topics {
+		sb.WriteString("<h3>" + gohtml.EscapeString(t.Title) + "</h3>\n")
+		if t.Raised() == 0 {
+			sb.WriteString("<p>Nothing raised.</p>\n")
+		}
+		for _, g := range groups(t) {
+			if len(g.nodes) == 0 {
+				continue
+			}
+			sb.WriteString("<h4>" + g.name + "</h4>\n<ul>\n")
+			for _, n := range g.nodes {
+				sb.WriteString("<li>" + html.Inline(children(n)) + "</li>\n")
+			}
+			sb.WriteString("</ul>\n")
+		}
+	}
+
+	if len(m.Actions) > 0 {
+		sb.WriteString("<h2>Actions</h2>\n<ul>\n")
+		for _, a := range m.Actions {
+			sb.WriteString("<li>" + html.Inline(a.Nodes) + "</li>\n")
+		}
+		sb.WriteString("</ul>\n")
+	}
+	return sb.String()
+}
+
+// Plain renders the minutes as plain text.
+func Plain(m Minutes) string {
+	sb := &strings.Builder{}
+	sb.WriteString("Minutes: " + m.Title + "\n")
+	if !m.Date.IsZero() {
+		sb.WriteString("Date: " + m.Date.Format(dateLayout) + "\n")
+	}
+
+	if len(m.Attendees) > 0 {
+		sb.WriteString("\nAttendees\n")
+		for _, s := range m.Attendees {
+			sb.WriteString("  - " + s + "\n")
+		}
+	}
+
+	if len(m.Topics) > 0 {
+		sb.WriteString("\nTopics\n")
+	}
+	for _, t := range m.Topics {
+		sb.WriteString("  " + t.Title + "\n")
+		if t.Raised() == 0 {
+			sb.WriteString("    Nothing raised.\n")
+		}
+		for _, g := range groups(t) {
+			if len(g.nodes) == 0 {
+				continue
+			}
+			sb.WriteString("    " + g.name + "\n")
+			for _, n := range g.nodes {
+				sb.WriteString("      - " + plainText(children(n)) + "\n")
+			}
+		}
+	}
+
+	if len(m.Actions) > 0 {
+		sb.WriteString("\nActions\n")
+		for _, a := range m.Actions {
+			sb.WriteString("  - " + plainText(a.Nodes) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func plainText(ns []ast.Node) string {
+	sb := &strings.Builder{}
+	for _, n := range ns {
+		writePlain(sb, n)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func writePlain(sb *strings.Builder, n ast.Node) {
+	switch n.Type() {
+	case ast.Tag:
+		sb.WriteString("#" + n.Text())
+	case ast.Link, ast.WikiLink:
+		sb.WriteString(n.Text())
+	default:
+		if p, ok := n.(ast.Parent); ok {
+			for _, c := range p.Nodes() {
+				writePlain(sb, c)
+			}
+		} else {
+			sb.WriteString(n.Text())
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package notebook
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+var errNotDir = errors.New("not a directory")
+
+// dateLayouts are the layouts of the dates recognised within artifacts and
+// file names, most specific first.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseDate parses 's' as a date or datetime, e.g. '2021-02-06' or
+// '2021-02-06 14:30'. Surrounding whitespace is ignored.
+func ParseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, e := time.Parse(layout, s); e == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseDatePrefix parses the first one or two words of 's' as a date, the
+// longest match wins.
+func parseDatePrefix(s string) (time.Time, bool) {
+	words := strings.Fields(s)
+	for n := 2; n > 0; n-- {
+		if len(words) < n {
+			continue
+		}
+		if t, ok := ParseDate(strings.Join(words[:n], " ")); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// MetaOf derives the metadata of 'notes' read from the file named 'name'.
+func MetaOf(name string, notes ast.Notes) Meta {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+
+	m := Meta{
+		Title: base,
+		Dates: Dates(notes),
+		Words: Words(notes),
+		Lines: len(notes),
+	}
+
+	for _, n := range notes {
+		if n.Type() != ast.Topic {
+			continue
+		}
+		if s := strings.TrimSpace(n.Text()); s != "" {
+			m.Title = s
+			break
+		}
+	}
+
+	if t, ok := ParseDate(base); ok {
+		m.Date = t
+	} else if len(m.Dates) > 0 {
+		m.Date = m.Dates[0]
+	}
+
+	return m
+}
+
+// Dates returns the dates found within the artifacts of 'notes', in order.
+// Because artifacts often run to the end of the line, e.g. '$2021-02-06 at
+// the dairy', only the start of each artifact needs to be a date.
+func Dates(notes ast.Notes) []time.Time {
+	r := []time.Time{}
+	ast.DescendNotes(notes, func(n ast.Node, _, _, _ int) {
+		if n.Type() != ast.Artifact {
+			return
+		}
+		if t, ok := parseDatePrefix(n.Text()); ok {
+			r = append(r, t)
+		}
+	})
+	return r
+}
+
+// Words returns the number of whitespace separated words within 'notes'.
+func Words(notes ast.Notes) int {
+	n := 0
+	for _, line := range notes {
+		n += len(strings.Fields(line.Text()))
+	}
+	return n
+}
@@ -0,0 +1,232 @@
+// Package notebook provides access to a directory tree of notes. Files are
+// parsed when first read and cached until their modification time or size
+// changes. A Notebook is safe for concurrent use.
+package notebook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+)
+
+// Ext is the file extension of Daft Wullie notes.
+const Ext = ".dw"
+
+type (
+	// Notebook is a directory tree of notes.
+	Notebook struct {
+		root  string
+		mu    sync.Mutex
+		cache map[string]*Note
+	}
+
+	// Note is a parsed note file. Notes are shared between readers so must be
+	// treated as read only.
+	Note struct {
+		Path    string // Path relative to the notebook root using '/'
+		File    string // Path of the file on disk
+		ModTime time.Time
+		Size    int64
+		Notes   ast.Notes
+		Meta    Meta
+	}
+
+	// Meta is information derived from the content of a note.
+	Meta struct {
+		Title string      // First topic or, if none, the file name
+		Date  time.Time   // Date in the file name or, if none, the first date
+		Dates []time.Time // Dates found within artifacts, in order
+		Words int
+		Lines int
+	}
+)
+
+// Open opens the notebook rooted at the directory 'root'.
+func Open(root string) (*Notebook, error) {
+	info, e := os.Stat(root)
+	if e != nil {
+		return nil, e
+	}
+	if !info.IsDir() {
+		return nil, &os.PathError{Op: "open", Path: root, Err: errNotDir}
+	}
+	return &Notebook{
+		root:  root,
+		cache: map[string]*Note{},
+	}, nil
+}
+
+// Root returns the root directory of the notebook.
+func (nb *Notebook) Root() string {
+	return nb.root
+}
+
+// Paths returns the paths, relative to the root, of every note file in the
+// notebook in lexical order. Hidden files and directories are skipped.
+func (nb *Notebook) Paths() ([]string, error) {
+	r := []string{}
+	e := filepath.Walk(nb.root, func(p string, info os.FileInfo, e error) error {
+		if e != nil {
+			return e
+		}
+
+		hidden := strings.HasPrefix(info.Name(), ".") && p != nb.root
+		switch {
+		case info.IsDir() && hidden:
+			return filepath.SkipDir
+		case info.IsDir(), hidden, filepath.Ext(p) != Ext:
+			return nil
+		}
+
+		rel, e := filepath.Rel(nb.root, p)
+		if e != nil {
+			return e
+		}
+		r = append(r, filepath.ToSlash(rel))
+		return nil
+	})
+
+	sort.Strings(r)
+	return r, e
+}
+
+// Note returns the note at 'path', relative to the root. The file is only
+// parsed if it has not been read before or has changed since.
+func (nb *Notebook) Note(path string) (*Note, error) {
+	file := filepath.Join(nb.root, filepath.FromSlash(path))
+
+	info, e := os.Stat(file)
+	if e != nil {
+		nb.forget(path)
+		return nil, e
+	}
+
+	nb.mu.Lock()
+	n, ok := nb.cache[path]
+	nb.mu.Unlock()
+
+	if ok && n.ModTime.Equal(info.ModTime()) && n.Size == info.Size() {
+		return n, nil
+	}
+
+	n, e = read(file, info)
+	if e != nil {
+		return nil, e
+	}
+	n.Path = path
+
+	nb.mu.Lock()
+	nb.cache[path] = n
+	nb.mu.Unlock()
+
+	return n, nil
+}
+
+// Notes returns every note in the notebook in lexical order of path. Changed
+// files are parsed concurrently.
+func (nb *Notebook) Notes() ([]*Note, error) {
+	paths, e := nb.Paths()
+	if e != nil {
+		return nil, e
+	}
+
+	r := make([]*Note, len(paths))
+	errs := make([]error, len(paths))
+	sem := make(chan struct{}, runtime.NumCPU())
+	wg := sync.WaitGroup{}
+
+	for i, p := range paths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, p string) {
+			defer func() { <-sem; wg.Done() }()
+			r[i], errs[i] = nb.Note(p)
+		}(i, p)
+	}
+	wg.Wait()
+
+	for _, e := range errs {
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	nb.prune(paths)
+	return r, nil
+}
+
+// Invalidate removes all notes from the cache so they are parsed again when
+// next read.
+func (nb *Notebook) Invalidate() {
+	nb.mu.Lock()
+	nb.cache = map[string]*Note{}
+	nb.mu.Unlock()
+}
+
+func (nb *Notebook) forget(path string) {
+	nb.mu.Lock()
+	delete(nb.cache, path)
+	nb.mu.Unlock()
+}
+
+// prune removes notes from the cache that are no longer in the notebook.
+func (nb *Notebook) prune(paths []string) {
+	keep := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		keep[p] = true
+	}
+
+	nb.mu.Lock()
+	for p := range nb.cache {
+		if !keep[p] {
+			delete(nb.cache, p)
+		}
+	}
+	nb.mu.Unlock()
+}
+
+// ReadNote reads and parses the note file at 'file' without a notebook. The
+// path of the note is the file path as given.
+func ReadNote(file string) (*Note, error) {
+	info, e := os.Stat(file)
+	if e != nil {
+		return nil, e
+	}
+
+	n, e := read(file, info)
+	if e != nil {
+		return nil, e
+	}
+	n.Path = filepath.ToSlash(file)
+	return n, nil
+}
+
+func read(file string, info os.FileInfo) (*Note, error) {
+	b, e := ioutil.ReadFile(file)
+	if e != nil {
+		return nil, e
+	}
+
+	notes := Parse(string(b))
+	return &Note{
+		File:    file,
+		ModTime: info.ModTime(),
+		Size:    info.Size(),
+		Notes:   notes,
+		Meta:    MetaOf(filepath.Base(file), notes),
+	}, nil
+}
+
+// Parse scans and parses the text 's' into notes.
+func Parse(s string) ast.Notes {
+	return ast.Notes(parser.ParseAll(scanner.ScanAllFlanking(s)))
+}
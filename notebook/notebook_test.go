@@ -0,0 +1,113 @@
+package notebook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, root, path, s string) {
+	file := filepath.Join(root, filepath.FromSlash(path))
+	require.Nil(t, os.MkdirAll(filepath.Dir(file), 0755))
+	require.Nil(t, ioutil.WriteFile(file, []byte(s), 0644))
+}
+
+func tempNotebook(t *testing.T) (*Notebook, func()) {
+	root, e := ioutil.TempDir("", "notebook")
+	require.Nil(t, e)
+
+	writeFile(t, root, "cheese.dw", "# Cheese\nMade on $2021-02-06 from milk")
+	writeFile(t, root, "types/2021-03-01.dw", "## Types\n. Brie\n. Stilton")
+	writeFile(t, root, "types/readme.txt", "Not a note")
+	writeFile(t, root, ".hidden/secret.dw", "# Secret")
+
+	nb, e := Open(root)
+	require.Nil(t, e)
+	return nb, func() { os.RemoveAll(root) }
+}
+
+func TestNotes_1(t *testing.T) {
+
+	nb, cleanup := tempNotebook(t)
+	defer cleanup()
+
+	notes, e := nb.Notes()
+	require.Nil(t, e)
+	require.Len(t, notes, 2)
+
+	cheese := notes[0]
+	require.Equal(t, "cheese.dw", cheese.Path)
+	require.Equal(t, "Cheese", cheese.Meta.Title)
+	require.Equal(t, 6, cheese.Meta.Words)
+	require.Equal(t, 2, cheese.Meta.Lines)
+	date := time.Date(2021, 2, 6, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, []time.Time{date}, cheese.Meta.Dates)
+	require.Equal(t, date, cheese.Meta.Date)
+
+	types := notes[1]
+	require.Equal(t, "types/2021-03-01.dw", types.Path)
+	require.Equal(t, "2021-03-01", types.Meta.Title)
+	require.Equal(t, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), types.Meta.Date)
+}
+
+func TestNote_1(t *testing.T) {
+
+	nb, cleanup := tempNotebook(t)
+	defer cleanup()
+
+	first, e := nb.Note("cheese.dw")
+	require.Nil(t, e)
+
+	again, e := nb.Note("cheese.dw")
+	require.Nil(t, e)
+	require.True(t, first == again, "Expected the cached note")
+
+	writeFile(t, nb.Root(), "cheese.dw", "# Cheddar")
+	later := first.ModTime.Add(time.Second)
+	require.Nil(t, os.Chtimes(first.File, later, later))
+
+	changed, e := nb.Note("cheese.dw")
+	require.Nil(t, e)
+	require.False(t, first == changed, "Expected the note to be parsed again")
+	require.Equal(t, "Cheddar", changed.Meta.Title)
+}
+
+func TestNotes_2(t *testing.T) {
+
+	nb, cleanup := tempNotebook(t)
+	defer cleanup()
+
+	type result struct {
+		notes []*Note
+		e     error
+	}
+
+	// require must be called from the test's own goroutine
+	results := make(chan result, 8)
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			notes, e := nb.Notes()
+			results <- result{notes, e}
+		}()
+	}
+
+	for i := 0; i < cap(results); i++ {
+		r := <-results
+		require.Nil(t, r.e)
+		require.Len(t, r.notes, 2)
+	}
+}
+
+func TestParse_1(t *testing.T) {
+
+	// Notes are read in flanking mode so symbols within words are text
+	notes := Parse("A well-known cheese, -smelly- and C++ $Mary-Jane$")
+	require.Equal(t, "A well-known cheese, smelly and C++ Mary-Jane", notes[0].Text())
+	require.Equal(t, "-smelly-", ast.FmtNode(notes[0].(ast.Parent).Nodes()[1]))
+}
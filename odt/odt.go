@@ -0,0 +1,285 @@
+// Package odt writes parsed notes as OpenDocument Text, e.g. for LibreOffice,
+// without any external tools.
+//
+// Topics use the common heading styles, 'Heading 1' to 'Heading 10', and
+// each is bookmarked with the slug of its title. Lists become 'text:list'
+// structures grouped and nested by block.Build, the same as every other
+// structured format. Phrases become spans with the named automatic styles:
+//   - key phrases, 'DWKeyPhrase'
+//   - positive phrases, 'DWPositive'
+//   - negative phrases, 'DWNegative'
+//   - artifacts, 'DWArtifact'
+//   - strong phrases, 'DWStrong'
+//   - tags, 'DWTag'
+//   - snippets, 'DWCode'
+//
+// Quotes are wrapped in curved double quotes. Wiki links link to the
+// OpenDocument file named after the slug of the note and links with unsafe
+// schemes, see html.SafeURL, are rendered as text.
+package odt
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+)
+
+// MimeType is the media type of OpenDocument Text files.
+const MimeType = "application/vnd.oasis.opendocument.text"
+
+// maxLevel is the deepest level of heading and list OpenDocument supports.
+const maxLevel = 10
+
+// epoch is the modification time of each part, the earliest a zip file can
+// record.
+var epoch = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// styles maps the phrase types to the names of their span styles.
+var styles = map[ast.NodeType]string{
+	ast.KeyPhrase: "DWKeyPhrase",
+	ast.Positive:  "DWPositive",
+	ast.Negative:  "DWNegative",
+	ast.Artifact:  "DWArtifact",
+	ast.Strong:    "DWStrong",
+	ast.Tag:       "DWTag",
+	ast.Snippet:   "DWCode",
+}
+
+// Render returns 'notes' as an OpenDocument Text file. The title of the
+// document is the first topic within the notes.
+func Render(notes ast.Notes) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if e := Write(buf, notes); e != nil {
+		return nil, e
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes 'notes' to 'w' as an OpenDocument Text file.
+func Write(w io.Writer, notes ast.Notes) error {
+	d := &document{bookmarks: map[string]bool{}}
+	for _, b := range block.Build(notes) {
+		d.writeBlock(b)
+	}
+
+	parts := []struct {
+		name   string
+		method uint16
+		data   string
+	}{
+		{"mimetype", zip.Store, MimeType}, // Must be first and uncompressed
+		{"META-INF/manifest.xml", zip.Deflate, manifest},
+		{"meta.xml", zip.Deflate, meta(html.Title(notes))},
+		{"styles.xml", zip.Deflate, stylesXML},
+		{"content.xml", zip.Deflate, contentStart + d.sb.String() + contentEnd},
+	}
+
+	z := zip.NewWriter(w)
+	for _, p := range parts {
+		f, e := z.CreateHeader(&zip.FileHeader{
+			Name:     p.name,
+			Method:   p.method,
+			Modified: epoch, // Keeps the output deterministic
+		})
+		if e != nil {
+			return e
+		}
+		if _, e := io.WriteString(f, p.data); e != nil {
+			return e
+		}
+	}
+	return z.Close()
+}
+
+// document accumulates the body of the document.
+type document struct {
+	sb        strings.Builder
+	bookmarks map[string]bool // Bookmark names already used
+	space     bool            // Last character written was a space
+}
+
+func (d *document) writeBlock(b block.Block) {
+	switch v := b.(type) {
+	case block.Heading:
+		level := strconv.Itoa(headingLevel(v.Level))
+		d.sb.WriteString(`<text:h text:style-name="Heading_20_` + level + `" text:outline-level="` + level + `">`)
+		if name := ast.Slug(ast.MakeTextLine(v.Nodes...).Text()); name != "" && !d.bookmarks[name] {
+			d.bookmarks[name] = true
+			d.sb.WriteString(`<text:bookmark text:name="` + escape(name) + `"/>`)
+		}
+		d.writePara(v.Nodes)
+		d.sb.WriteString("</text:h>")
+
+	case block.Para:
+		d.sb.WriteString(`<text:p text:style-name="Text_20_body">`)
+		d.writePara(v.Nodes)
+		d.sb.WriteString("</text:p>")
+
+	case *block.List:
+		d.writeList(v)
+	}
+}
+
+// writeList writes a list with each of its items holding a paragraph and any
+// lists nested within it. Items with an explicit number restart the
+// numbering.
+func (d *document) writeList(l *block.List) {
+	style := "DWBullets"
+	if l.Ordered {
+		style = "DWNumbers"
+	}
+
+	d.sb.WriteString(`<text:list text:style-name="` + style + `">`)
+	for i, item := range l.Items {
+		if l.Ordered && (i == 0 && item.Num != 1 || i > 0 && item.Start > 0) {
+			d.sb.WriteString(`<text:list-item text:start-value="` + strconv.Itoa(item.Num) + `">`)
+		} else {
+			d.sb.WriteString(`<text:list-item>`)
+		}
+
+		d.sb.WriteString(`<text:p text:style-name="List_20_Contents">`)
+		d.writePara(item.Nodes)
+		d.sb.WriteString("</text:p>")
+
+		for _, sub := range item.Lists {
+			d.writeList(sub)
+		}
+		d.sb.WriteString("</text:list-item>")
+	}
+	d.sb.WriteString("</text:list>")
+}
+
+// writePara writes the content of a paragraph.
+func (d *document) writePara(ns []ast.Node) {
+	d.space = true // Leading spaces would be collapsed
+	for _, n := range ns {
+		d.writeNode(n)
+	}
+}
+
+func (d *document) writeNode(n ast.Node) {
+
+	writeChildren := func(n ast.Node) {
+		if p, ok := n.(ast.Parent); ok {
+			for _, c := range p.Nodes() {
+				d.writeNode(c)
+			}
+		} else {
+			d.text(n.Text())
+		}
+	}
+
+	switch n.Type() {
+	case ast.KeyPhrase, ast.Positive, ast.Negative, ast.Artifact, ast.Strong:
+		d.sb.WriteString(`<text:span text:style-name="` + styles[n.Type()] + `">`)
+		writeChildren(n)
+		d.sb.WriteString("</text:span>")
+	case ast.Quote:
+		d.text("“")
+		writeChildren(n)
+		d.text("”")
+	case ast.Snippet:
+		d.sb.WriteString(`<text:span text:style-name="` + styles[ast.Snippet] + `">`)
+		d.text(n.Text())
+		d.sb.WriteString("</text:span>")
+	case ast.Tag:
+		d.sb.WriteString(`<text:span text:style-name="` + styles[ast.Tag] + `">`)
+		d.text("#" + n.Text())
+		d.sb.WriteString("</text:span>")
+	case ast.Link:
+		d.writeLink(n)
+	case ast.WikiLink:
+		d.writeWikiLink(n)
+	default:
+		writeChildren(n)
+	}
+}
+
+func (d *document) writeLink(n ast.Node) {
+	l, ok := n.(ast.LinkNode)
+	if !ok || !html.SafeURL(l.URL) {
+		d.text(n.Text())
+		return
+	}
+	d.writeHyperlink(l.URL, n.Text())
+}
+
+// writeWikiLink writes a wiki link as a link to the OpenDocument file named
+// after the slug of the note and, if given, the bookmark of the topic.
+func (d *document) writeWikiLink(n ast.Node) {
+	l, ok := n.(ast.WikiLinkNode)
+	if !ok {
+		d.text(n.Text())
+		return
+	}
+
+	target := "../" + ast.Slug(l.Note) + ".odt" // Relative to the package
+	if l.Topic != "" {
+		target += "#" + ast.Slug(l.Topic)
+	}
+	d.writeHyperlink(target, n.Text())
+}
+
+func (d *document) writeHyperlink(target, text string) {
+	d.sb.WriteString(`<text:a xlink:type="simple" xlink:href="` + escape(target) + `">`)
+	d.text(text)
+	d.sb.WriteString("</text:a>")
+}
+
+// text writes escaped text. OpenDocument collapses runs of spaces, and
+// removes leading spaces, so all but the first space of a run, or all of
+// them at the start of a paragraph, are written as 'text:s' elements. Tabs
+// are written as 'text:tab' elements.
+func (d *document) text(s string) {
+	spaces := 0
+	flush := func() {
+		if spaces > 0 {
+			d.sb.WriteString(`<text:s text:c="` + strconv.Itoa(spaces) + `"/>`)
+			spaces = 0
+		}
+	}
+
+	for _, ru := range s {
+		switch {
+		case ru == ' ' && d.space:
+			spaces++
+		case ru == ' ':
+			d.sb.WriteRune(' ')
+			d.space = true
+		case ru == '\t':
+			flush()
+			d.sb.WriteString("<text:tab/>")
+			d.space = false
+		default:
+			flush()
+			d.sb.WriteString(escape(string(ru)))
+			d.space = false
+		}
+	}
+	flush()
+}
+
+// escape escapes 's' for use as XML text or an attribute value.
+func escape(s string) string {
+	buf := &bytes.Buffer{}
+	xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
+
+func headingLevel(level int) int {
+	switch {
+	case level < 1:
+		return 1
+	case level > maxLevel:
+		return maxLevel
+	}
+	return level
+}
@@ -0,0 +1,74 @@
+package odt
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/internal/ziptest"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_1(t *testing.T) {
+
+	in := `# Cheese & Wine
+## Types
+. Chedder from $Somerset
+.. **Mild** and *mature*, see ` + "`man cheese`" + `
+. Brie
+-Smelly- but +tasty **ripe**+ [[Wine#Reds]] #dairy
+See [shop](https://a.org/c?a=1&b=2) not [this](javascript:x)
+
+!3 Curdling
+! Ripening
+!1 Salting`
+
+	b, e := Render(parser.ParseAll(scanner.ScanAll(in)))
+	require.Nil(t, e)
+
+	names, parts := ziptest.Unzip(t, b)
+	require.True(t, ziptest.Stored(t, b))
+	require.Equal(t, []string{"mimetype", "META-INF/manifest.xml", "meta.xml", "styles.xml", "content.xml"}, names)
+	require.Equal(t, MimeType, parts["mimetype"])
+	for _, name := range names[1:] {
+		ziptest.WellFormed(t, name, parts[name])
+	}
+
+	exp := `<text:h text:style-name="Heading_20_1" text:outline-level="1"><text:bookmark text:name="cheese-wine"/>Cheese &amp; Wine</text:h>` +
+		`<text:h text:style-name="Heading_20_2" text:outline-level="2"><text:bookmark text:name="types"/>Types</text:h>` +
+		`<text:list text:style-name="DWBullets">` +
+		`<text:list-item><text:p text:style-name="List_20_Contents">Chedder from <text:span text:style-name="DWArtifact">Somerset</text:span></text:p>` +
+		`<text:list text:style-name="DWBullets">` +
+		`<text:list-item><text:p text:style-name="List_20_Contents"><text:span text:style-name="DWKeyPhrase">Mild</text:span> and <text:span text:style-name="DWStrong">mature</text:span>, see <text:span text:style-name="DWCode">man cheese</text:span></text:p></text:list-item>` +
+		`</text:list></text:list-item>` +
+		`<text:list-item><text:p text:style-name="List_20_Contents">Brie</text:p></text:list-item>` +
+		`</text:list>` +
+		`<text:p text:style-name="Text_20_body"><text:span text:style-name="DWNegative">Smelly</text:span> but <text:span text:style-name="DWPositive">tasty <text:span text:style-name="DWKeyPhrase">ripe</text:span></text:span>` +
+		` <text:a xlink:type="simple" xlink:href="../wine.odt#reds">Wine#Reds</text:a> <text:span text:style-name="DWTag">#dairy</text:span></text:p>` +
+		`<text:p text:style-name="Text_20_body">See <text:a xlink:type="simple" xlink:href="https://a.org/c?a=1&amp;b=2">shop</text:a> not this</text:p>` +
+		`<text:list text:style-name="DWNumbers">` +
+		`<text:list-item text:start-value="3"><text:p text:style-name="List_20_Contents">Curdling</text:p></text:list-item>` +
+		`<text:list-item><text:p text:style-name="List_20_Contents">Ripening</text:p></text:list-item>` +
+		`<text:list-item text:start-value="1"><text:p text:style-name="List_20_Contents">Salting</text:p></text:list-item>` +
+		`</text:list>`
+
+	require.Equal(t, contentStart+exp+contentEnd, parts["content.xml"])
+	require.Contains(t, parts["meta.xml"], `<dc:title>Cheese &amp; Wine</dc:title>`)
+}
+
+func TestText_1(t *testing.T) {
+	d := &document{}
+	d.writePara([]ast.Node{ast.MakeText("  a  b\tc <d> ")})
+	require.Equal(t, `<text:s text:c="2"/>a <text:s text:c="1"/>b<text:tab/>c &lt;d&gt; `, d.sb.String())
+}
+
+func TestRender_2(t *testing.T) {
+	notes := parser.ParseAll(scanner.ScanAll("# Title\nSome text"))
+	a, e := Render(notes)
+	require.Nil(t, e)
+	b, e := Render(notes)
+	require.Nil(t, e)
+	require.Equal(t, a, b)
+}
@@ -0,0 +1,136 @@
+package odt
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// namespaces declares the namespaces used by the document parts.
+const namespaces = ` xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"` +
+	` xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"` +
+	` xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"` +
+	` xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"` +
+	` xmlns:svg="urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"` +
+	` xmlns:xlink="http://www.w3.org/1999/xlink"` +
+	` office:version="1.2"`
+
+const manifest = xml.Header +
+	`<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.2">` +
+	`<manifest:file-entry manifest:full-path="/" manifest:version="1.2" manifest:media-type="` + MimeType + `"/>` +
+	`<manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>` +
+	`<manifest:file-entry manifest:full-path="styles.xml" manifest:media-type="text/xml"/>` +
+	`<manifest:file-entry manifest:full-path="meta.xml" manifest:media-type="text/xml"/>` +
+	`</manifest:manifest>`
+
+// meta returns the metadata part holding the title of the document.
+func meta(title string) string {
+	return xml.Header +
+		`<office:document-meta` +
+		` xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"` +
+		` xmlns:dc="http://purl.org/dc/elements/1.1/"` +
+		` office:version="1.2">` +
+		`<office:meta><dc:title>` + escape(title) + `</dc:title></office:meta>` +
+		`</office:document-meta>`
+}
+
+const fontFaces = `<office:font-face-decls>` +
+	`<style:font-face style:name="Liberation Sans" svg:font-family="'Liberation Sans'" style:font-family-generic="swiss" style:font-pitch="variable"/>` +
+	`<style:font-face style:name="Liberation Mono" svg:font-family="'Liberation Mono'" style:font-family-generic="modern" style:font-pitch="fixed"/>` +
+	`</office:font-face-decls>`
+
+// contentStart opens the content part with the automatic styles of phrases.
+// The colours match those of html.Style.
+const contentStart = xml.Header +
+	`<office:document-content` + namespaces + `>` +
+	fontFaces +
+	`<office:automatic-styles>` +
+	`<style:style style:name="DWKeyPhrase" style:family="text">` +
+	`<style:text-properties fo:background-color="#fff3a0"/></style:style>` +
+	`<style:style style:name="DWPositive" style:family="text">` +
+	`<style:text-properties fo:color="#1a7f37" style:text-underline-style="solid" style:text-underline-width="auto" style:text-underline-color="font-color"/></style:style>` +
+	`<style:style style:name="DWNegative" style:family="text">` +
+	`<style:text-properties fo:color="#cf222e" style:text-underline-style="wave" style:text-underline-width="auto" style:text-underline-color="font-color"/></style:style>` +
+	`<style:style style:name="DWArtifact" style:family="text">` +
+	`<style:text-properties fo:color="#6639ba" fo:font-style="italic"/></style:style>` +
+	`<style:style style:name="DWStrong" style:family="text">` +
+	`<style:text-properties fo:font-weight="bold"/></style:style>` +
+	`<style:style style:name="DWTag" style:family="text">` +
+	`<style:text-properties fo:color="#0969da"/></style:style>` +
+	`<style:style style:name="DWCode" style:family="text">` +
+	`<style:text-properties style:font-name="Liberation Mono" fo:background-color="#f0f0f0"/></style:style>` +
+	`</office:automatic-styles>` +
+	`<office:body><office:text>`
+
+const contentEnd = `</office:text></office:body></office:document-content>`
+
+// stylesXML defines the paragraph styles of topics, paragraphs, and list
+// items, and the bulleted and numbered list styles.
+var stylesXML = xml.Header +
+	`<office:document-styles` + namespaces + `>` +
+	fontFaces +
+	`<office:styles>` +
+	`<style:default-style style:family="paragraph">` +
+	`<style:text-properties style:font-name="Liberation Sans" fo:font-size="11pt" fo:language="en" fo:country="GB"/>` +
+	`</style:default-style>` +
+	`<style:style style:name="Standard" style:family="paragraph" style:class="text"/>` +
+	`<style:style style:name="Text_20_body" style:display-name="Text body" style:family="paragraph" style:parent-style-name="Standard" style:class="text">` +
+	`<style:paragraph-properties fo:margin-top="0cm" fo:margin-bottom="0.21cm"/></style:style>` +
+	`<style:style style:name="List_20_Contents" style:display-name="List Contents" style:family="paragraph" style:parent-style-name="Text_20_body" style:class="list">` +
+	`<style:paragraph-properties fo:margin-top="0cm" fo:margin-bottom="0.07cm"/></style:style>` +
+	`<style:style style:name="Heading" style:family="paragraph" style:parent-style-name="Standard" style:next-style-name="Text_20_body" style:class="text">` +
+	`<style:paragraph-properties fo:margin-top="0.42cm" fo:margin-bottom="0.14cm" fo:keep-with-next="always"/>` +
+	`<style:text-properties fo:color="#1f3864" fo:font-weight="bold"/></style:style>` +
+	headings(16, 14, 13, 12, 11, 11, 11, 11, 11, 11) +
+	listStyle("DWBullets", false) +
+	listStyle("DWNumbers", true) +
+	`</office:styles>` +
+	`</office:document-styles>`
+
+// headings returns the paragraph styles of topics at each level, with the
+// font size in points of each level in turn.
+func headings(sizes ...int) string {
+	sb := strings.Builder{}
+	for i, size := range sizes {
+		n := strconv.Itoa(i + 1)
+		sb.WriteString(`<style:style style:name="Heading_20_` + n + `" style:display-name="Heading ` + n + `"`)
+		sb.WriteString(` style:family="paragraph" style:parent-style-name="Heading" style:next-style-name="Text_20_body"`)
+		sb.WriteString(` style:default-outline-level="` + n + `" style:class="text">`)
+		sb.WriteString(`<style:text-properties fo:font-size="` + strconv.Itoa(size) + `pt"/></style:style>`)
+	}
+	return sb.String()
+}
+
+// bullets are the bullet characters of each level of a bulleted list.
+var bullets = []string{"•", "◦", "▪"}
+
+// listStyle returns a bulleted or numbered list style indenting each level
+// by a further 0.635cm, or a quarter of an inch.
+func listStyle(name string, ordered bool) string {
+	sb := strings.Builder{}
+	sb.WriteString(`<text:list-style style:name="` + name + `">`)
+
+	for i := 0; i < maxLevel; i++ {
+		level := strconv.Itoa(i + 1)
+		if ordered {
+			sb.WriteString(`<text:list-level-style-number text:level="` + level + `" style:num-suffix="." style:num-format="1">`)
+		} else {
+			sb.WriteString(`<text:list-level-style-bullet text:level="` + level + `" text:bullet-char="` + bullets[i%len(bullets)] + `">`)
+		}
+
+		indent := strconv.FormatFloat(0.635*float64(i+2), 'f', 3, 64)
+		sb.WriteString(`<style:list-level-properties text:list-level-position-and-space-mode="label-alignment">`)
+		sb.WriteString(`<style:list-level-label-alignment text:label-followed-by="listtab"`)
+		sb.WriteString(` text:list-tab-stop-position="` + indent + `cm" fo:text-indent="-0.635cm" fo:margin-left="` + indent + `cm"/>`)
+		sb.WriteString(`</style:list-level-properties>`)
+
+		if ordered {
+			sb.WriteString(`</text:list-level-style-number>`)
+		} else {
+			sb.WriteString(`</text:list-level-style-bullet>`)
+		}
+	}
+
+	sb.WriteString(`</text:list-style>`)
+	return sb.String()
+}
@@ -0,0 +1,508 @@
+package org
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+var (
+	headlinePattern = regexp.MustCompile(`^(\*+)(?:[ \t]+(.*?))?[ \t]*$`)
+	tagsPattern     = regexp.MustCompile(`[ \t]+:((?:[\w@#%]+:)+)$`)
+	itemPattern     = regexp.MustCompile(`^([ \t]*)([-+*]|\d+[.)])(?:[ \t]+(.*))?$`)
+	cookiePattern   = regexp.MustCompile(`^\[@(\d+)\][ \t]*`)
+	blockPattern    = regexp.MustCompile(`(?i)^[ \t]*#\+(begin|end)_(\w+)`)
+	drawerPattern   = regexp.MustCompile(`(?i)^[ \t]*:(properties|logbook):[ \t]*$`)
+	endPattern      = regexp.MustCompile(`(?i)^[ \t]*:end:[ \t]*$`)
+)
+
+// makers maps the names of the macros phrases are exported as to the
+// functions that make the phrases.
+var makers = map[string]func(...ast.Node) ast.ParentNode{
+	"dwkey":      ast.MakeKeyPhrase,
+	"dwpos":      ast.MakePositive,
+	"dwneg":      ast.MakeNegative,
+	"dwartifact": ast.MakeArtifact,
+}
+
+// entities maps the names of common Org entities, e.g. '\ast{}', to the text
+// they stand for.
+var entities = map[string]string{
+	"backslash": `\`,
+	"ast":       "*",
+	"slash":     "/",
+	"under":     "_",
+	"plus":      "+",
+	"equal":     "=",
+	"tilde":     "~",
+	"vert":      "|",
+	"dollar":    "$",
+	"asciicirc": "^",
+	"amp":       "&",
+	"lt":        "<",
+	"gt":        ">",
+	"nbsp":      " ",
+	"hellip":    "…",
+	"ndash":     "–",
+	"mdash":     "—",
+	"larr":      "←",
+	"rarr":      "→",
+	"deg":       "°",
+	"times":     "×",
+	"pound":     "£",
+	"euro":      "€",
+	"copy":      "©",
+}
+
+// Import converts the Org document 's' into notes. The conventions described
+// by the package documentation are reversed so Daft Wullie notes exported
+// with Render are imported unchanged, except that runs of empty lines become
+// a single empty line. Other Org documents are converted as follows:
+//   - headline tags become tags at the end of the topic, e.g. '# Topic #tag'
+//   - bold becomes strong, code and verbatim become snippets, and all other
+//     emphasis is removed
+//   - source and example blocks, and fixed width lines, become lines of
+//     snippets
+//   - keywords, comments, and property and logbook drawers are dropped
+//   - text Daft Wullie would read as symbols is escaped
+func Import(s string) ast.Notes {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	notes := ast.Notes{}
+	indents := []int{} // Indents of the open lists, deepest last
+	verbatim, drawer, header := false, false, false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if m := blockPattern.FindStringSubmatch(line); m != nil {
+			kind := strings.ToLower(m[2])
+			verbatim = strings.EqualFold(m[1], "begin") && (kind == "src" || kind == "example")
+			continue
+		}
+
+		switch {
+		case verbatim:
+			notes = append(notes, snippetLine(trimmed))
+			continue
+		case drawer:
+			drawer = !endPattern.MatchString(line)
+			continue
+		case drawerPattern.MatchString(line):
+			drawer = true
+			continue
+		case isKeyword(trimmed):
+			header = header || len(notes) == 0
+			continue
+		case header && trimmed == "" && len(notes) == 0:
+			header = false // Blank line separating the header
+			continue
+		}
+		header = false
+
+		if trimmed == ":" || strings.HasPrefix(trimmed, ": ") {
+			notes = append(notes, snippetLine(strings.TrimSpace(trimmed[1:])))
+			continue
+		}
+
+		if trimmed == "" {
+			notes = append(notes, ast.MakeEmptyLine())
+			continue
+		}
+
+		if m := headlinePattern.FindStringSubmatch(line); m != nil {
+			indents = indents[:0]
+			notes = append(notes, headline(len(m[1]), m[2]))
+			continue
+		}
+
+		if m := itemPattern.FindStringSubmatch(line); m != nil && (m[2] != "*" || m[1] != "") {
+			indent := len(m[1])
+			for len(indents) > 0 && indents[len(indents)-1] > indent {
+				indents = indents[:len(indents)-1]
+			}
+			if len(indents) == 0 || indents[len(indents)-1] < indent {
+				indents = append(indents, indent)
+			}
+
+			notes = append(notes, item(len(indents), m[2], m[3]))
+			continue
+		}
+
+		if line == strings.TrimLeftFunc(line, unicode.IsSpace) {
+			indents = indents[:0]
+		}
+		notes = append(notes, ast.MakeTextLine(parseInline(trimLineBreak(trimmed), false)...))
+	}
+
+	return notes
+}
+
+// isKeyword returns true if the line 's' is a keyword, e.g. '#+TITLE: x', or
+// a comment.
+func isKeyword(s string) bool {
+	return strings.HasPrefix(s, "#+") || s == "#" || strings.HasPrefix(s, "# ")
+}
+
+func snippetLine(s string) ast.Node {
+	if s == "" {
+		return ast.MakeEmptyLine()
+	}
+	return ast.MakeTextLine(ast.MakeSnippet(s))
+}
+
+// headline makes a topic from the text of a headline. Topics are plain text
+// so markup is removed.
+func headline(level int, s string) ast.Node {
+	tags := ""
+	if m := tagsPattern.FindStringSubmatchIndex(s); m != nil {
+		for _, t := range strings.Split(strings.Trim(s[m[2]:m[3]], ":"), ":") {
+			tags += " #" + t
+		}
+		s = s[:m[0]]
+	}
+
+	sb := strings.Builder{}
+	for _, n := range parseInline(s, false) {
+		if n.Type() == ast.Tag {
+			sb.WriteString("#")
+		}
+		sb.WriteString(n.Text())
+	}
+
+	title := strings.TrimSpace(sb.String() + tags)
+	if title == "" {
+		return ast.MakeHeading(level)
+	}
+	return ast.MakeHeading(level, ast.MakeText(" "+title))
+}
+
+// item makes a list item nested to 'depth' from the 'marker' and the text
+// following it.
+func item(depth int, marker, s string) ast.Node {
+	li := ast.MakeListItem(depth, !strings.ContainsAny(marker, "-+*"))
+
+	if m := cookiePattern.FindStringSubmatch(s); m != nil && li.Ordered {
+		li.Start, _ = strconv.Atoi(m[1])
+		s = s[len(m[0]):]
+	}
+
+	if s = trimLineBreak(s); s != "" {
+		li.Children = append([]ast.Node{ast.MakeText(" ")}, parseInline(s, false)...)
+	}
+	return li
+}
+
+// trimLineBreak removes the line break, '\\', from the end of 's'.
+func trimLineBreak(s string) string {
+	s = strings.TrimRightFunc(s, unicode.IsSpace)
+	if strings.HasSuffix(s, `\\`) {
+		s = strings.TrimRightFunc(strings.TrimSuffix(s, `\\`), unicode.IsSpace)
+	}
+	return s
+}
+
+// inline accumulates the phrase nodes of a line, merging adjacent text.
+type inline struct {
+	nodes []ast.Node
+	text  strings.Builder
+}
+
+func (in *inline) flush() {
+	if in.text.Len() > 0 {
+		in.nodes = append(in.nodes, ast.MakeText(in.text.String()))
+		in.text.Reset()
+	}
+}
+
+func (in *inline) add(ns ...ast.Node) {
+	for _, n := range ns {
+		if n.Type() == ast.Text {
+			in.text.WriteString(n.Text())
+			continue
+		}
+		in.flush()
+		in.nodes = append(in.nodes, n)
+	}
+}
+
+// parseInline converts the Org markup within 's' into phrase nodes. If 'arg'
+// is true, 's' is the argument of a macro and '\,' is an escaped comma.
+func parseInline(s string, arg bool) []ast.Node {
+	rs := []rune(s)
+	in := &inline{}
+	searches := map[string]*search{}
+
+	// closerAt returns the index of the first 'closer', found by 'f', at or
+	// after 'from' or -1 if there isn't one
+	closerAt := func(closer string, from int, f func(int) int) int {
+		c, ok := searches[closer]
+		if !ok {
+			c = &search{}
+			searches[closer] = c
+		}
+		return c.find(from, f)
+	}
+
+	at := func(i int) rune {
+		if i < 0 || i >= len(rs) {
+			return ' '
+		}
+		return rs[i]
+	}
+
+	for i := 0; i < len(rs); i++ {
+		v, prev, next := rs[i], at(i-1), at(i+1)
+
+		switch {
+		case v == '\u200b': // Escapes markup, see Escape
+
+		case v == '\\' && arg && next == ',':
+			in.text.WriteRune(',')
+			i++
+
+		case v == '\\':
+			j := i + 1
+			for ; j < len(rs) && unicode.IsLetter(rs[j]); j++ {
+			}
+			if e, ok := entities[string(rs[i+1:j])]; ok {
+				in.text.WriteString(e)
+				if hasPrefix(rs, j, "{}") {
+					j += 2
+				}
+				i = j - 1
+			} else {
+				in.text.WriteRune(v)
+			}
+
+		case hasPrefix(rs, i, "{{{"):
+			end := -1
+			if closerAt(")}}}", i, func(j int) int { return find(rs, j, ")}}}") }) >= 0 {
+				end = macroEnd(rs, i)
+			}
+			if end < 0 {
+				in.text.WriteRune(v)
+				break
+			}
+
+			body := string(rs[i+3 : end-3]) // name(args)
+			open := strings.IndexByte(body, '(')
+			if mk, ok := makers[body[:maxInt(open, 0)]]; ok && open > 0 {
+				in.add(mk(parseInline(body[open+1:len(body)-1], true)...))
+			} else {
+				in.text.WriteString(string(rs[i:end]))
+			}
+			i = end - 1
+
+		case hasPrefix(rs, i, "[["):
+			end := closerAt("]]", i+2, func(j int) int { return find(rs, j, "]]") })
+			if end < 0 {
+				in.text.WriteRune(v)
+				break
+			}
+
+			target, desc := string(rs[i+2:end]), ""
+			if j := strings.Index(target, "]["); j >= 0 {
+				target, desc = target[:j], target[j+2:]
+			}
+			in.add(makeLink(target, strings.ReplaceAll(desc, zwsp, "")))
+			i = end + 1
+
+		case v == '"' && next != '\u200b':
+			j := i + 1
+			for ; j < len(rs) && (rs[j] != '"' || at(j+1) == '\u200b'); j++ {
+			}
+			if j >= len(rs) || j == i+1 {
+				in.text.WriteRune(v)
+				break
+			}
+			in.add(ast.MakeQuote(parseInline(string(rs[i+1:j]), arg)...))
+			i = j
+
+		case v == '#' && isTagStart(prev, next):
+			j := i + 1
+			for ; j < len(rs) && isTagRune(rs[j]); j++ {
+			}
+			for rs[j-1] == '-' || rs[j-1] == '/' {
+				j--
+			}
+			in.add(ast.MakeTag(string(rs[i+1 : j])))
+			i = j - 1
+
+		case strings.ContainsRune("*/_+=~", v) && strings.ContainsRune(openers, prev) &&
+			!unicode.IsSpace(next) && next != '\u200b':
+
+			j := closerAt(string(v), i+2, func(j int) int { return emphasisEnd(rs, j) })
+			if j < 0 {
+				in.text.WriteRune(v)
+				break
+			}
+
+			inner := string(rs[i+1 : j])
+			switch v {
+			case '=', '~':
+				in.add(ast.MakeSnippet(inner))
+			case '*':
+				in.add(ast.MakeStrong(parseInline(inner, arg)...))
+			default:
+				in.add(parseInline(inner, arg)...)
+			}
+			i = j
+
+		case !isWordRune(prev) && isURL(rs, i):
+			j := i
+			for ; j < len(rs) && !unicode.IsSpace(rs[j]); j++ {
+			}
+			for j > i && strings.ContainsRune(".,;:!?)", rs[j-1]) {
+				j--
+			}
+			in.add(ast.MakeLink(string(rs[i:j]), ""))
+			i = j - 1
+
+		default:
+			in.text.WriteRune(v)
+		}
+	}
+
+	in.flush()
+	return in.nodes
+}
+
+// macroEnd returns the index after the ')}}}' closing the macro starting at
+// index 'i' of 'rs', allowing for nested macros, or -1 if it isn't closed.
+func macroEnd(rs []rune, i int) int {
+	depth := 0
+	for j := i; j < len(rs); j++ {
+		switch {
+		case hasPrefix(rs, j, "{{{"):
+			depth++
+			j += 2
+		case hasPrefix(rs, j, ")}}}"):
+			depth--
+			j += 3
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return -1
+}
+
+// emphasisEnd returns the index of the first marker, the same as the one
+// before index 'from' of 'rs', that could close emphasis at or after 'from'
+// or -1 if there isn't one.
+func emphasisEnd(rs []rune, from int) int {
+	for j := from; j < len(rs); j++ {
+		if rs[j] != rs[from-2] || unicode.IsSpace(rs[j-1]) {
+			continue
+		}
+		if j+1 == len(rs) || strings.ContainsRune(closers, rs[j+1]) {
+			return j
+		}
+	}
+	return -1
+}
+
+// makeLink makes a wiki link from a link to an Org or Daft Wullie file, a
+// link from a link with a safe scheme, and text from all other links.
+func makeLink(target, desc string) ast.Node {
+	if strings.HasPrefix(target, "file:") {
+		file, search := target[len("file:"):], ""
+		if i := strings.Index(file, "::"); i >= 0 {
+			file, search = file[:i], file[i+2:]
+		}
+
+		if ext := path.Ext(file); ext == ".org" || ext == ".dw" {
+			if desc != "" {
+				note, topic := desc, ""
+				if i := strings.Index(desc, "#"); i >= 0 {
+					note, topic = desc[:i], desc[i+1:]
+				}
+				return ast.MakeWikiLink(note, topic)
+			}
+			note := strings.TrimSuffix(path.Base(file), ext)
+			return ast.MakeWikiLink(note, strings.TrimPrefix(search, "*"))
+		}
+	}
+
+	if isURL([]rune(target), 0) {
+		return ast.MakeLink(target, desc)
+	}
+
+	if desc != "" {
+		return ast.MakeText(desc)
+	}
+	return ast.MakeText(target)
+}
+
+// isURL returns true if a URL, with a safe scheme and an address, starts at
+// index 'i' of 'rs'.
+func isURL(rs []rune, i int) bool {
+	for _, scheme := range []string{"https://", "http://", "ftp://", "mailto:"} {
+		if hasPrefix(rs, i, scheme) && len(rs) > i+len(scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// search caches the result of searching forwards through a line so later
+// searches for the same thing don't rescan it. This keeps parsing linear
+// when a line has many openers but no closer.
+type search struct {
+	done     bool
+	from, at int
+}
+
+// find returns the result of 'f', the index of the first match at or after
+// 'from' or -1, reusing the last result if it still holds.
+func (c *search) find(from int, f func(int) int) int {
+	if c.done && c.from <= from && (c.at < 0 || c.at >= from) {
+		return c.at
+	}
+	c.done, c.from, c.at = true, from, f(from)
+	return c.at
+}
+
+// find returns the index of the first 's' within 'rs' at or after index
+// 'from' or -1 if there isn't one.
+func find(rs []rune, from int, s string) int {
+	for i := from; i < len(rs); i++ {
+		if hasPrefix(rs, i, s) {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasPrefix(rs []rune, i int, prefix string) bool {
+	for _, ru := range prefix {
+		if i >= len(rs) || rs[i] != ru {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+func isWordRune(ru rune) bool {
+	return unicode.IsLetter(ru) || unicode.IsDigit(ru)
+}
+
+func isTagRune(ru rune) bool {
+	return isWordRune(ru) || ru == '_' || ru == '-' || ru == '/'
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,331 @@
+// Package org converts notes to and from Emacs Org mode.
+//
+// Topics become headlines and lists become plain and ordered lists. Explicit
+// list numbers, e.g. '!5', become counter cookies, e.g. '5. [@5]'. Org has
+// no equivalent for some Daft Wullie phrases so the following conventions
+// are used:
+//   - key phrases, positive, negative, and artifact phrases become the
+//     macros 'dwkey', 'dwpos', 'dwneg', and 'dwartifact', e.g.
+//     '{{{dwpos(cheap)}}}', defined by Header for the HTML exporter
+//   - strong phrases become bold, '*bold*', and snippets become code,
+//     '~code~', or verbatim, '=verbatim=', if they contain a tilde, but
+//     snippets containing both, or starting or ending with whitespace, become
+//     text
+//   - quotes are wrapped in double quotes
+//   - wiki links become file links to the Org file named after the slug of
+//     the note, e.g. '[[file:other-note.org::*Topic][Other Note#Topic]]'
+//   - links with unsafe schemes, see html.SafeURL, are rendered as text
+//
+// Text that Org would read as markup is escaped with zero width spaces as
+// the Org manual suggests. Import removes them again.
+//
+// Org's exporter only expands the outermost of nested macros, e.g. a key
+// phrase within a positive phrase, but they survive conversion back to Daft
+// Wullie.
+package org
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+)
+
+// zwsp is the zero width space used to escape markup.
+const zwsp = "\u200b"
+
+// Header defines the macros phrases are converted to and requires braces
+// for sub and superscripts so underscores within words are left alone.
+const Header = `#+OPTIONS: ^:{}
+#+MACRO: dwkey @@html:<mark class="dw-key-phrase">@@$1@@html:</mark>@@
+#+MACRO: dwpos @@html:<span class="dw-positive">@@$1@@html:</span>@@
+#+MACRO: dwneg @@html:<span class="dw-negative">@@$1@@html:</span>@@
+#+MACRO: dwartifact @@html:<span class="dw-artifact">@@$1@@html:</span>@@
+`
+
+// macros maps the phrase types to the names of the macros they become.
+var macros = map[ast.NodeType]string{
+	ast.KeyPhrase: "dwkey",
+	ast.Positive:  "dwpos",
+	ast.Negative:  "dwneg",
+	ast.Artifact:  "dwartifact",
+}
+
+// Render renders 'notes' as an Org document starting with the Header.
+func Render(notes ast.Notes) string {
+	return Header + "\n" + Fragment(notes)
+}
+
+// Fragment renders 'notes' as Org without the Header.
+func Fragment(notes ast.Notes) string {
+	lines := []string{}
+
+	var prev block.Block
+	for _, b := range block.Build(notes) {
+		if _, ok := prev.(block.Para); ok {
+			if _, ok := b.(block.Para); ok {
+				lines[len(lines)-1] += ` \\` // Keep the lines apart
+			}
+		}
+		lines = append(lines, writeBlock(b))
+		prev = b
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Inline renders a sequence of phrase nodes as inline Org.
+func Inline(ns []ast.Node) string {
+	r := &renderer{sb: &strings.Builder{}}
+	r.writeNodes(ns)
+	return r.sb.String()
+}
+
+type renderer struct {
+	sb    *strings.Builder
+	macro int // Depth of macro arguments being written
+}
+
+// writeBlock returns the Org of a block without a trailing line feed.
+func writeBlock(b block.Block) string {
+	switch v := b.(type) {
+	case block.Heading:
+		s := escapeHeadingStart(Inline(v.Nodes))
+		if strings.HasSuffix(s, ":") {
+			s += zwsp // Not headline tags
+		}
+		return strings.Repeat("*", v.Level) + " " + s
+
+	case block.Para:
+		return escapeLineStart(Inline(v.Nodes))
+
+	case *block.List:
+		r := &renderer{sb: &strings.Builder{}}
+		r.writeList(v, "")
+		return strings.TrimSuffix(r.sb.String(), "\n")
+	}
+	return ""
+}
+
+func (r *renderer) writeList(l *block.List, indent string) {
+	for i, item := range l.Items {
+		marker := "- "
+		if l.Ordered {
+			marker = strconv.Itoa(item.Num) + ". "
+		}
+
+		r.sb.WriteString(indent + marker)
+		if l.Ordered && (i == 0 && item.Num != 1 || item.Start > 0) {
+			r.sb.WriteString("[@" + strconv.Itoa(item.Num) + "] ")
+		}
+
+		s := Inline(item.Nodes)
+		if strings.HasPrefix(s, "[") {
+			s = zwsp + s // Not a checkbox or counter cookie
+		}
+		r.sb.WriteString(s)
+		r.sb.WriteString("\n")
+
+		for _, sub := range item.Lists {
+			r.writeList(sub, indent+strings.Repeat(" ", len(marker)))
+		}
+	}
+}
+
+func (r *renderer) writeNodes(ns []ast.Node) {
+	for _, n := range ns {
+		r.writeNode(n)
+	}
+}
+
+func (r *renderer) writeNode(n ast.Node) {
+
+	writeGroup := func(open string, n ast.Node, close string) {
+		r.sb.WriteString(open)
+		if p, ok := n.(ast.Parent); ok {
+			r.writeNodes(p.Nodes())
+		} else {
+			r.writeText(n.Text())
+		}
+		r.sb.WriteString(close)
+	}
+
+	switch n.Type() {
+	case ast.KeyPhrase, ast.Positive, ast.Negative, ast.Artifact:
+		r.macro++
+		writeGroup("{{{"+macros[n.Type()]+"(", n, ")}}}")
+		r.macro--
+	case ast.Strong:
+		writeGroup("*", n, "*")
+	case ast.Quote:
+		writeGroup(`"`, n, `"`)
+	case ast.Snippet:
+		r.writeSnippet(n.Text())
+	case ast.Link:
+		r.writeLink(n)
+	case ast.WikiLink:
+		r.writeWikiLink(n)
+	case ast.Tag:
+		r.sb.WriteString("#" + n.Text())
+	default:
+		writeGroup("", n, "")
+	}
+}
+
+func (r *renderer) writeText(s string) {
+	s = Escape(s)
+	if r.macro > 0 {
+		s = strings.ReplaceAll(s, ",", `\,`)
+	}
+	r.sb.WriteString(s)
+}
+
+// writeSnippet writes 's' as code or, if it contains a tilde, as verbatim
+// text. Snippets Org cannot mark up are written as text.
+func (r *renderer) writeSnippet(s string) {
+	if s == "" || s != strings.TrimSpace(s) || strings.ContainsAny(s, "\r\n") {
+		r.writeText(s)
+		return
+	}
+
+	for _, m := range []string{"~", "="} {
+		if !strings.Contains(s, m) {
+			if r.macro > 0 {
+				s = strings.ReplaceAll(s, ",", `\,`)
+			}
+			r.sb.WriteString(m + s + m)
+			return
+		}
+	}
+	r.writeText(s)
+}
+
+func (r *renderer) writeLink(n ast.Node) {
+	l, ok := n.(ast.LinkNode)
+	switch {
+	case !ok, !html.SafeURL(l.URL):
+		r.writeText(n.Text())
+	case l.Label == "":
+		r.sb.WriteString("[[" + escapeTarget(l.URL) + "]]")
+	default:
+		r.sb.WriteString("[[" + escapeTarget(l.URL) + "][" + escapeDesc(l.Label) + "]]")
+	}
+}
+
+// writeWikiLink writes a wiki link as a link to the Org file named after the
+// slug of the note and, if given, the headline of the topic.
+func (r *renderer) writeWikiLink(n ast.Node) {
+	l, ok := n.(ast.WikiLinkNode)
+	if !ok {
+		r.writeText(n.Text())
+		return
+	}
+
+	target := "file:" + ast.Slug(l.Note) + ".org"
+	if l.Topic != "" {
+		target += "::*" + l.Topic
+	}
+	r.sb.WriteString("[[" + escapeTarget(target) + "][" + escapeDesc(l.Text()) + "]]")
+}
+
+// Escape escapes text within 's' that Org would otherwise read as markup,
+// e.g. emphasis, links, macros, entities, or LaTeX fragments, by inserting
+// zero width spaces. The start and end of 's' are assumed to be word
+// boundaries.
+func Escape(s string) string {
+	ru := []rune(s)
+	sb := strings.Builder{}
+
+	for i, v := range ru {
+		var prev, next rune = ' ', ' '
+		if i > 0 {
+			prev = ru[i-1]
+		}
+		if i+1 < len(ru) {
+			next = ru[i+1]
+		}
+
+		switch {
+		case strings.ContainsRune("*/_=~+", v):
+			if strings.ContainsRune(openers, prev) {
+				sb.WriteString(zwsp)
+			}
+			sb.WriteRune(v)
+			if strings.ContainsRune(closers, next) {
+				sb.WriteString(zwsp)
+			}
+
+		case v == '[' && next == '[', v == '{' && next == '{', v == '\\', v == '$', v == '"':
+			sb.WriteRune(v)
+			sb.WriteString(zwsp)
+
+		case v == '#' && isTagStart(prev, next):
+			sb.WriteRune(v)
+			sb.WriteString(zwsp)
+
+		default:
+			sb.WriteRune(v)
+		}
+	}
+
+	return sb.String()
+}
+
+// openers and closers are the runes that may precede and follow Org
+// emphasis markers respectively.
+const (
+	openers = " \t-('\"{"
+	closers = " \t-.,;:!?')}[\"\\"
+)
+
+// escapeLineStart escapes the start of a paragraph that would otherwise be
+// read as a headline, list item, comment, keyword, table, or fixed width
+// text.
+func escapeLineStart(s string) string {
+	if s == "" {
+		return s
+	}
+
+	if strings.ContainsRune("*-+#:|", rune(s[0])) {
+		return zwsp + s
+	}
+
+	i := 0
+	for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+	}
+	if i > 0 && i < len(s) && (s[i] == '.' || s[i] == ')') {
+		return zwsp + s
+	}
+
+	return s
+}
+
+// escapeHeadingStart escapes the start of a headline's title that would
+// otherwise be read as a TODO or COMMENT keyword. Priority cookies, e.g.
+// '[#A]', are already broken up by Escape.
+func escapeHeadingStart(s string) string {
+	for _, kw := range []string{"TODO", "DONE", "COMMENT"} {
+		if s == kw || strings.HasPrefix(s, kw+" ") {
+			return zwsp + s
+		}
+	}
+	return s
+}
+
+func escapeTarget(s string) string {
+	return strings.NewReplacer("[", "%5B", "]", "%5D").Replace(s)
+}
+
+func escapeDesc(s string) string {
+	return strings.ReplaceAll(s, "]", "]"+zwsp)
+}
+
+// isTagStart returns true if a '#' between 'prev' and 'next' starts a tag.
+func isTagStart(prev, next rune) bool {
+	return !isWordRune(prev) && (isWordRune(next) || next == '_')
+}
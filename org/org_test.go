@@ -0,0 +1,142 @@
+package org
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parse(s string) ast.Notes {
+	return parser.ParseAll(scanner.ScanAll(s))
+}
+
+func TestFragment_1(t *testing.T) {
+
+	in := `# Cheese
+## Types
+. Chedder from $Somerset
+.. Mild *and* mature, see ` + "`man cheese`" + `
+-Smelly, very- but **ripe** [[Wine#Reds]]
+Tagged #cheese and [shop](https://a.org/c) not [this](javascript:x)
+
+!3 Curdling
+! Ripening`
+
+	exp := `* Cheese
+** Types
+- Chedder from {{{dwartifact(Somerset)}}}
+  - Mild *and* mature, see ~man cheese~
+{{{dwneg(Smelly\, very)}}} but {{{dwkey(ripe)}}} [[file:wine.org::*Reds][Wine#Reds]] \\
+Tagged #cheese and [[https://a.org/c][shop]] not this
+
+3. [@3] Curdling
+4. Ripening
+`
+
+	require.Equal(t, exp, Fragment(parse(in)))
+}
+
+func TestEscape_1(t *testing.T) {
+	require.Equal(t, "a "+zwsp+"*b*"+zwsp+" and/or", Escape("a *b* and/or"))
+	require.Equal(t, "["+zwsp+"[x]] {"+zwsp+"{"+zwsp+"{ $"+zwsp+"1", Escape("[[x]] {{{ $1"))
+	require.Equal(t, zwsp+"- x", escapeLineStart("- x"))
+	require.Equal(t, zwsp+"1. x", escapeLineStart("1. x"))
+
+	act := Fragment(parse("# TODO list\n## COMMENT\n## [#A] first\n## TODOs"))
+	require.Equal(t, "* "+zwsp+"TODO list\n** "+zwsp+"COMMENT\n** [#"+zwsp+"A] first\n** TODOs\n", act)
+}
+
+func TestRoundTrip_1(t *testing.T) {
+
+	// Each note survives conversion to Org and back unchanged
+	in := []string{
+		"# Cheese\n## Types\n### Soft",
+		". Chedder from $Somerset$\n.. Mild *and* mature\n. Brie",
+		"!3 Curdling\n! Ripening\n!! Salting\n!!5 Pressing",
+		"+cheap+ but -smelly, very- and **ripe**\nA \"quote\" with `code`",
+		"+Positive with **key** inside+ and $2021-02-06$",
+		"Links [shop](https://a.org/c) https://b.org and [[Wine#Reds]] [[Beer]]",
+		"Tagged #cheese #dairy/soft",
+		`Escaped \*not strong\* \+not positive\+ and a\_b =c= ~d~ 2/3 \$5`,
+		"Org markup *a* /b/ _c_ =d= ~e~ +f+ [[g]] {{{h}}} \\alpha",
+		`\. not a list` + "\n" + `\# not a topic` + "\n" + `\!1 not numbered`,
+		"\\- not a list\n\\* not a headline\n1. not a list\n# comment\n: fixed\n| table |",
+		". [ ] not a checkbox\n! [@2] not a cookie",
+		"# Topic with a :colon:\nA `snippet with ~`",
+		"# TODO list\n## DONE\n## [#B] second",
+		"Text\n\nMore text\nAnd more",
+	}
+
+	for _, s := range in {
+		notes := parse(s)
+		exp := ast.FmtString(notes)
+		act := ast.FmtString(Import(Render(notes)))
+		require.Equal(t, exp, act, "Org:\n%s", Render(notes))
+	}
+}
+
+func TestImport_1(t *testing.T) {
+
+	in := `#+TITLE: Cheese
+#+OPTIONS: toc:nil
+
+* Cheese :dairy:food:
+:PROPERTIES:
+:ID: 123
+:END:
+Some *strong* and /italic/ text with =code= \\
+and a link to [[https://a.org][A]] or https://b.org.
+# A comment
+** TODO Types
+- Chedder
+  - Mild
+  + Mature
+- Brie costs $5
+1. [@3] First
+2. Second
+
+#+BEGIN_SRC go
+fmt.Println("hi")
+#+END_SRC
+: fixed width
+An \deg{} and \ast{}.`
+
+	exp := `# Cheese #dairy #food
+Some *strong* and italic text with ` + "`code`" + `
+and a link to [A](https://a.org) or https://b.org.
+## TODO Types
+. Chedder
+.. Mild
+.. Mature
+. Brie costs \$5
+!3 First
+! Second
+
+` + "`fmt.Println(\"hi\")`" + `
+` + "`fixed width`" + `
+An ° and \*.
+`
+
+	require.Equal(t, exp, ast.FmtString(Import(in)))
+}
+
+func TestImport_2(t *testing.T) {
+
+	// Closers are searched for once per line, not once per opener
+	in := "*one* and *two* [[file:a.org]] [[file:b.org]] " + strings.Repeat("*a [[b {{{c ", 4000) + "end"
+	exp := "*one* and *two* [[a]] [[b]] " + strings.Repeat(`\*a \[\[b {{{c `, 4000) + "end\n"
+
+	require.Equal(t, exp, ast.FmtString(Import(in)))
+}
+
+func TestRender_1(t *testing.T) {
+	act := Render(parse("# Cheese"))
+	require.True(t, strings.HasPrefix(act, Header+"\n"))
+	require.True(t, strings.HasSuffix(act, "\n* Cheese\n"))
+	require.Equal(t, "# Cheese\n", ast.FmtString(Import(act)))
+}
@@ -3,6 +3,7 @@
 package parser
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/PaulioRandall/daft-wullie-go/ast"
@@ -50,35 +51,53 @@ func parser(r *lineReader) ParseLine {
 
 // LINE := *Nothing/empty*
 // LINE := (TOPIC | SUB_TOPIC) TEXT_LINE
-// LINE := [BUL_POINT | NUM_POINT] NODE_LINE
+// LINE := [BUL_POINT | SUB_BUL_POINT | NUM_POINT | SUB_NUM_POINT] NODE_LINE
 func parseLine(r *tokenReader) ast.Node {
 	switch {
 	case !r.more():
 		return ast.MakeEmptyLine()
 
-	case r.accept(token.Topic):
-		return ast.MakeTopic(parseNodes(r)...)
+	case r.match(token.Topic), r.match(token.SubTopic):
+		level := depthOf(r.read())
+		return ast.MakeHeading(level, parseNodes(r)...)
 
-	case r.accept(token.SubTopic):
-		return ast.MakeSubTopic(parseNodes(r)...)
+	case r.match(token.BulPoint), r.match(token.SubBulPoint):
+		depth := depthOf(r.read())
+		return ast.MakeListItem(depth, false, parseNodes(r)...)
 
-	case r.accept(token.BulPoint):
-		return ast.MakeBulPoint(parseNodes(r)...)
-
-	case r.accept(token.SubBulPoint):
-		return ast.MakeSubBulPoint(parseNodes(r)...)
-
-	case r.accept(token.NumPoint):
-		return ast.MakeNumPoint(parseNodes(r)...)
-
-	case r.accept(token.SubNumPoint):
-		return ast.MakeSubNumPoint(parseNodes(r)...)
+	case r.match(token.NumPoint), r.match(token.SubNumPoint):
+		lx := r.read()
+		n := ast.MakeListItem(depthOf(lx), true, parseNodes(r)...)
+		n.Start = startOf(lx)
+		return n
 
 	default:
 		return ast.MakeTextLine(parseNodes(r)...)
 	}
 }
 
+// depthOf returns the nesting depth of a line node lexeme, i.e. the number of
+// times its symbol is repeated.
+func depthOf(lx token.Lexeme) int {
+	n := 0
+	for ; n < len(lx.Val) && lx.Val[n] == lx.Val[0]; n++ {
+	}
+	if n > 1 {
+		return n
+	}
+	return 1
+}
+
+// startOf returns the explicit start number following the symbols of a
+// numbered point lexeme, e.g. '!5', or zero if there isn't one.
+func startOf(lx token.Lexeme) int {
+	n, e := strconv.Atoi(strings.TrimLeft(lx.Val, "!"))
+	if e != nil {
+		return 0
+	}
+	return n
+}
+
 // NODE_LINE := {NODE} *EOF*
 func parseNodes(r *tokenReader) []ast.Node {
 	ns := []ast.Node{}
@@ -96,6 +115,9 @@ func parseNodes(r *tokenReader) []ast.Node {
 // NODE := QUOTE      {NODE} [QUOTE]
 // NODE := ARTIFACT   {NODE} [ARTIFACT]
 // NODE := SNIPPET    {NODE} [SNIPPET]
+// NODE := LINK
+// NODE := WIKI_LINK
+// NODE := TAG
 // NODE := TEXT_PHRASE
 func parseNode(r *tokenReader) ast.Node {
 	switch {
@@ -120,11 +142,44 @@ func parseNode(r *tokenReader) ast.Node {
 	case r.accept(token.Snippet):
 		return ast.MakeSnippet(parseTextUntil(r, token.Snippet))
 
+	case r.match(token.Link):
+		return parseLink(r.read())
+
+	case r.match(token.WikiLink):
+		return parseWikiLink(r.read())
+
+	case r.match(token.Tag):
+		return ast.MakeTag(r.read().Val[1:])
+
 	default:
 		return ast.MakeText(parseText(r))
 	}
 }
 
+// LINK := *bare URL*
+// LINK := '[' *label* '](' *URL* ')'
+func parseLink(lx token.Lexeme) ast.Node {
+	s := lx.Val
+	if !strings.HasPrefix(s, "[") {
+		return ast.MakeLink(s, "")
+	}
+
+	i := strings.Index(s, "](")
+	return ast.MakeLink(s[i+2:len(s)-1], s[1:i])
+}
+
+// WIKI_LINK := '[[' *note* ['#' *topic*] ']]'
+func parseWikiLink(lx token.Lexeme) ast.Node {
+	s := lx.Val[2 : len(lx.Val)-2]
+	topic := ""
+
+	if i := strings.Index(s, "#"); i >= 0 {
+		s, topic = s[:i], s[i+1:]
+	}
+
+	return ast.MakeWikiLink(strings.TrimSpace(s), strings.TrimSpace(topic))
+}
+
 // parseNodesUntil parses child nodes until the end of the line or the
 // specified 'delim' is encountered. Upon which, the delim is read and
 // discarded before the children are returned.
@@ -21,8 +21,8 @@ func TestHeadings_1(t *testing.T) {
 	}
 
 	exp := []ast.Node{
-		ast.MakeTopic(ast.MakeText("1")),
-		ast.MakeSubTopic(ast.MakeText("2")),
+		ast.MakeHeading(1, ast.MakeText("1")),
+		ast.MakeHeading(2, ast.MakeText("2")),
 	}
 
 	act := ParseAll(in)
@@ -39,7 +39,7 @@ func TestBulPoint_1(t *testing.T) {
 	}
 
 	exp := []ast.Node{
-		ast.MakeBulPoint(
+		ast.MakeListItem(1, false,
 			ast.MakeText("The Turtle Moves!"),
 		),
 	}
@@ -58,7 +58,7 @@ func TestSubBulPoint_1(t *testing.T) {
 	}
 
 	exp := []ast.Node{
-		ast.MakeSubBulPoint(
+		ast.MakeListItem(2, false,
 			ast.MakeText("The Turtle Moves!"),
 		),
 	}
@@ -77,7 +77,7 @@ func TestNumPoint_1(t *testing.T) {
 	}
 
 	exp := []ast.Node{
-		ast.MakeNumPoint(
+		ast.MakeListItem(1, true,
 			ast.MakeText("The Turtle Moves!"),
 		),
 	}
@@ -96,7 +96,7 @@ func TestSubNumPoint_1(t *testing.T) {
 	}
 
 	exp := []ast.Node{
-		ast.MakeSubNumPoint(
+		ast.MakeListItem(2, true,
 			ast.MakeText("The Turtle Moves!"),
 		),
 	}
@@ -105,6 +105,93 @@ func TestSubNumPoint_1(t *testing.T) {
 	require.Equal(t, exp, act)
 }
 
+func TestNumPoint_2(t *testing.T) {
+
+	in := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.NumPoint, "!5"),
+			lex(token.Text, "The Turtle Moves!"),
+		},
+		[]token.Lexeme{
+			lex(token.SubNumPoint, "!!12"),
+			lex(token.Text, "The Turtle Moves!"),
+		},
+	}
+
+	first := ast.MakeListItem(1, true, ast.MakeText("The Turtle Moves!"))
+	first.Start = 5
+	second := ast.MakeListItem(2, true, ast.MakeText("The Turtle Moves!"))
+	second.Start = 12
+
+	exp := []ast.Node{first, second}
+
+	act := ParseAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestLink_1(t *testing.T) {
+
+	in := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Link, "https://example.com"),
+			lex(token.Text, " "),
+			lex(token.Link, "[Example](https://example.com/a_(b))"),
+		},
+	}
+
+	exp := []ast.Node{
+		ast.MakeTextLine(
+			ast.MakeLink("https://example.com", ""),
+			ast.MakeText(" "),
+			ast.MakeLink("https://example.com/a_(b)", "Example"),
+		),
+	}
+
+	act := ParseAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestWikiLink_1(t *testing.T) {
+
+	in := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.WikiLink, "[[Other Note]]"),
+			lex(token.WikiLink, "[[ Other Note # Topic ]]"),
+		},
+	}
+
+	exp := []ast.Node{
+		ast.MakeTextLine(
+			ast.MakeWikiLink("Other Note", ""),
+			ast.MakeWikiLink("Other Note", "Topic"),
+		),
+	}
+
+	act := ParseAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestNesting_1(t *testing.T) {
+
+	in := [][]token.Lexeme{
+		[]token.Lexeme{lex(token.SubTopic, "###"), lex(token.Text, "3")},
+		[]token.Lexeme{lex(token.SubBulPoint, "..."), lex(token.Text, "3")},
+		[]token.Lexeme{lex(token.SubNumPoint, "!!!!"), lex(token.Text, "4")},
+	}
+
+	exp := []ast.Node{
+		ast.MakeHeading(3, ast.MakeText("3")),
+		ast.MakeListItem(3, false, ast.MakeText("3")),
+		ast.MakeListItem(4, true, ast.MakeText("4")),
+	}
+
+	act := ParseAll(in)
+	require.Equal(t, exp, act)
+	require.Equal(t, ast.NodeType(ast.SubTopic), act[0].Type())
+	require.Equal(t, ast.NodeType(ast.SubBulPoint), act[1].Type())
+	require.Equal(t, ast.NodeType(ast.SubNumPoint), act[2].Type())
+}
+
 func TestNestableNodes_1(t *testing.T) {
 
 	lxs := func(tk token.Token, v string) []token.Lexeme {
@@ -241,7 +328,7 @@ func TestScript_1(t *testing.T) {
 	}
 
 	exp := []ast.Node{ // Lines
-		ast.MakeTopic(
+		ast.MakeHeading(1,
 			ast.MakeText("Cheese"),
 		),
 		ast.MakeTextLine(
@@ -267,7 +354,7 @@ func TestScript_1(t *testing.T) {
 		),
 		ast.MakeEmptyLine(),
 
-		ast.MakeSubTopic(
+		ast.MakeHeading(2,
 			ast.MakeText("History"),
 		),
 		ast.MakeTextLine(ast.MakeText("Who knows.")),
@@ -275,28 +362,28 @@ func TestScript_1(t *testing.T) {
 		ast.MakeEmptyLine(),
 		ast.MakeEmptyLine(),
 
-		ast.MakeSubTopic(
+		ast.MakeHeading(2,
 			ast.MakeText("Types"),
 		),
-		ast.MakeBulPoint(
+		ast.MakeListItem(1, false,
 			ast.MakeText("Chedder, always from "),
 			ast.MakeArtifact(ast.MakeText("Chedder,Somerset,England")),
 		),
-		ast.MakeBulPoint(ast.MakeText("Brie")),
-		ast.MakeBulPoint(ast.MakeText("Mozzarella")),
-		ast.MakeBulPoint(ast.MakeText("Stilton")),
-		ast.MakeBulPoint(ast.MakeText("etc")),
+		ast.MakeListItem(1, false, ast.MakeText("Brie")),
+		ast.MakeListItem(1, false, ast.MakeText("Mozzarella")),
+		ast.MakeListItem(1, false, ast.MakeText("Stilton")),
+		ast.MakeListItem(1, false, ast.MakeText("etc")),
 		ast.MakeEmptyLine(),
 
-		ast.MakeSubTopic(
+		ast.MakeHeading(2,
 			ast.MakeText("Process"),
 		),
-		ast.MakeNumPoint(ast.MakeText("Curdling")),
-		ast.MakeNumPoint(ast.MakeText("Curd processing")),
-		ast.MakeNumPoint(ast.MakeText("Ripening")),
+		ast.MakeListItem(1, true, ast.MakeText("Curdling")),
+		ast.MakeListItem(1, true, ast.MakeText("Curd processing")),
+		ast.MakeListItem(1, true, ast.MakeText("Ripening")),
 		ast.MakeEmptyLine(),
 
-		ast.MakeSubTopic(
+		ast.MakeHeading(2,
 			ast.MakeText("Bacteria"),
 		),
 		ast.MakeTextLine(
@@ -306,7 +393,7 @@ func TestScript_1(t *testing.T) {
 		),
 		ast.MakeEmptyLine(),
 
-		ast.MakeSubTopic(
+		ast.MakeHeading(2,
 			ast.MakeText("Heart disease"),
 		),
 		ast.MakeTextLine(
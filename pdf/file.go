@@ -0,0 +1,176 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// font is one of the standard Type1 fonts every PDF reader provides.
+type font int
+
+const (
+	regular font = iota
+	bold
+	oblique
+	boldOblique
+	mono
+)
+
+// baseFonts are the PostScript names of the fonts.
+var baseFonts = []string{
+	"Helvetica",
+	"Helvetica-Bold",
+	"Helvetica-Oblique",
+	"Helvetica-BoldOblique",
+	"Courier",
+}
+
+// name returns the resource name of the font used within content streams.
+func (f font) name() string {
+	return "/F" + strconv.Itoa(int(f)+1)
+}
+
+// width returns the width of 's', encoded with WinAnsiEncoding, in text
+// space units at a font size of one.
+func (f font) width(s string) float64 {
+	n := 0
+	for _, b := range []byte(s) {
+		switch {
+		case f == mono:
+			n += 600
+		case b < 32:
+			n += 278
+		case f == bold || f == boldOblique:
+			n += helveticaBoldWidths[b-32]
+		default:
+			n += helveticaWidths[b-32]
+		}
+	}
+	return float64(n) / 1000
+}
+
+// winAnsi maps the runes within 0x80 to 0x9F of WinAnsiEncoding to their
+// codes. Other runes up to 0xFF are the same as Latin-1.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode encodes 's' with WinAnsiEncoding. Runes it cannot represent are
+// replaced with '?'.
+func encode(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, ru := range s {
+		switch c, ok := winAnsi[ru]; {
+		case ok:
+			b = append(b, c)
+		case ru == '\t':
+			b = append(b, ' ')
+		case ru >= 0x20 && ru < 0x7F, ru >= 0xA0 && ru <= 0xFF:
+			b = append(b, byte(ru))
+		default:
+			b = append(b, '?')
+		}
+	}
+	return string(b)
+}
+
+// literal returns the encoded string 's' as a PDF literal string.
+func literal(s string) string {
+	sb := strings.Builder{}
+	sb.WriteByte('(')
+	for _, b := range []byte(s) {
+		switch {
+		case b == '(', b == ')', b == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(b)
+		case b < 0x20 || b > 0x7E:
+			fmt.Fprintf(&sb, "\\%03o", b)
+		default:
+			sb.WriteByte(b)
+		}
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// textString returns 's' as a PDF text string, i.e. UTF-16BE with a byte
+// order mark, for use outside of content streams such as the document title.
+func textString(s string) string {
+	sb := strings.Builder{}
+	sb.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// num formats 'f' with at most two decimal places.
+func num(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
+
+// file writes the objects of a PDF file recording the offset of each for the
+// cross reference table.
+type file struct {
+	buf     bytes.Buffer
+	offsets []int // Offset of each object, object numbers start from one
+}
+
+// reserve returns the number of a new object to be written later.
+func (f *file) reserve() int {
+	f.offsets = append(f.offsets, 0)
+	return len(f.offsets)
+}
+
+// object writes the object numbered 'n'.
+func (f *file) object(n int, content string) {
+	f.offsets[n-1] = f.buf.Len()
+	fmt.Fprintf(&f.buf, "%d 0 obj\n%s\nendobj\n", n, content)
+}
+
+// stream writes the object numbered 'n' as a compressed stream.
+func (f *file) stream(n int, data []byte) error {
+	z := bytes.Buffer{}
+	w := zlib.NewWriter(&z)
+	if _, e := w.Write(data); e != nil {
+		return e
+	}
+	if e := w.Close(); e != nil {
+		return e
+	}
+
+	f.offsets[n-1] = f.buf.Len()
+	fmt.Fprintf(&f.buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", n, z.Len())
+	f.buf.Write(z.Bytes())
+	f.buf.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+// writeTo writes the file, with its cross reference table and trailer, to
+// 'w'.
+func (f *file) writeTo(w io.Writer, root, info int) error {
+	xref := f.buf.Len()
+	fmt.Fprintf(&f.buf, "xref\n0 %d\n0000000000 65535 f \n", len(f.offsets)+1)
+	for _, off := range f.offsets {
+		fmt.Fprintf(&f.buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&f.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\n", len(f.offsets)+1, root, info)
+	fmt.Fprintf(&f.buf, "startxref\n%d\n%%%%EOF\n", xref)
+
+	_, e := w.Write(f.buf.Bytes())
+	return e
+}
@@ -0,0 +1,629 @@
+// Package pdf renders parsed notes as PDF documents using only the standard
+// Type1 fonts, Helvetica and Courier, so no fonts or external tools are
+// needed.
+//
+// Topics become bold headings, paragraphs are wrapped to the width of an A4
+// page, and lists are indented with bullets or numbers, breaking onto new
+// pages as needed. Phrases are styled so they remain distinguishable when
+// printed in black and white:
+//   - key phrases are highlighted
+//   - positive phrases are green and underlined
+//   - negative phrases are red with a wavy underline
+//   - artifacts are purple and oblique
+//   - strong phrases are bold and snippets use Courier
+//
+// Quotes are wrapped in curved double quotes. Links with safe schemes, see
+// html.SafeURL, are clickable. Wiki links link to the PDF file named after the
+// slug of the note, opened at the topic, since each topic is a named
+// destination.
+//
+// The standard fonts only cover the WinAnsiEncoding, i.e. Latin-1 and common
+// punctuation, so other characters are rendered as '?'.
+package pdf
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+)
+
+// Page dimensions and margins of A4 paper in points.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 56.69 // 2cm
+	footer     = 20    // Space for the page number
+	bodySize   = 11
+	listIndent = 18
+)
+
+// Colours as RGB components, the same as html.Style.
+const (
+	black     = "0 0 0"
+	grey      = "0.4 0.4 0.4"
+	headingFg = "0.122 0.22 0.392"
+	keyBg     = "1 0.953 0.627"
+	positive  = "0.102 0.498 0.216"
+	negative  = "0.812 0.133 0.18"
+	artifact  = "0.4 0.224 0.729"
+	blue      = "0.035 0.412 0.855"
+)
+
+// headingSizes are the font sizes of topics at each level, deeper levels use
+// the last.
+var headingSizes = []float64{20, 16, 13.5, 12}
+
+// bullets are the markers of each level of a bulleted list.
+var bullets = []string{"•", "–", "·"}
+
+// underline is the style of line drawn under a run of text.
+type underline int
+
+const (
+	noLine underline = iota
+	solidLine
+	wavyLine
+)
+
+// style is the formatting of a run of text.
+type style struct {
+	font      font
+	size      float64
+	colour    string
+	underline underline
+	highlight bool
+	link      string // Action of a link annotation, empty if none
+}
+
+// run is text of the same style encoded with WinAnsiEncoding.
+type run struct {
+	text  string
+	style style
+	width float64
+}
+
+// word is a sequence of runs without spaces between them along with the
+// space, if any, that preceded it.
+type word struct {
+	space *run
+	runs  []*run
+	width float64
+}
+
+// Render returns 'notes' as a PDF document. The title of the document is the
+// first topic within the notes.
+func Render(notes ast.Notes) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if e := Write(buf, notes); e != nil {
+		return nil, e
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes 'notes' to 'w' as a PDF document.
+func Write(w io.Writer, notes ast.Notes) error {
+	r := &renderer{dests: map[string]dest{}}
+	for _, b := range block.Build(notes) {
+		r.writeBlock(b)
+	}
+	if len(r.pages) == 0 {
+		r.newPage()
+	}
+	return r.writeTo(w, html.Title(notes))
+}
+
+// page is the content stream and link annotations of a page.
+type page struct {
+	content strings.Builder
+	annots  []annot
+}
+
+// annot is a link annotation, 'rect' is the lower left and upper right
+// corners.
+type annot struct {
+	rect   [4]float64
+	action string
+}
+
+// dest is a named destination, the top of a topic.
+type dest struct {
+	page int
+	y    float64
+}
+
+type renderer struct {
+	pages []*page
+	page  *page
+	y     float64 // Top of the next line
+	dests map[string]dest
+	names []string // Destination names in order of appearance
+}
+
+func (r *renderer) newPage() {
+	r.page = &page{}
+	r.pages = append(r.pages, r.page)
+	r.y = pageHeight - margin
+
+	n := encode(strconv.Itoa(len(r.pages)))
+	x := (pageWidth - regular.width(n)*9) / 2
+	r.page.content.WriteString("BT " + regular.name() + " 9 Tf " + grey + " rg " + num(x) + " " + num(margin/2) + " Td " + literal(n) + " Tj ET\n")
+}
+
+// ensure starts a new page unless there's 'h' points of space left on the
+// current one.
+func (r *renderer) ensure(h float64) {
+	if r.page == nil || r.y-h < margin+footer {
+		r.newPage()
+	}
+}
+
+// gap leaves 'h' points of space unless at the top of a page.
+func (r *renderer) gap(h float64) {
+	if r.page != nil && r.y < pageHeight-margin {
+		r.y -= h
+	}
+}
+
+func (r *renderer) writeBlock(b block.Block) {
+	width := pageWidth - 2*margin
+
+	switch v := b.(type) {
+	case block.Heading:
+		size := headingSizes[len(headingSizes)-1]
+		if v.Level <= len(headingSizes) {
+			size = headingSizes[v.Level-1]
+		}
+		st := style{font: bold, size: size, colour: headingFg}
+		lines := wrap(words(v.Nodes, st), width)
+
+		r.gap(size * 0.6)
+		r.ensure(float64(len(lines))*lineHeight(size) + lineHeight(bodySize)) // Keeps it with the next line
+		if name := ast.Slug(ast.MakeTextLine(v.Nodes...).Text()); name != "" {
+			if _, ok := r.dests[name]; !ok {
+				r.dests[name] = dest{page: len(r.pages) - 1, y: r.y}
+				r.names = append(r.names, name)
+			}
+		}
+		r.writeLines(lines, margin, "", style{})
+		r.gap(size * 0.2)
+
+	case block.Para:
+		st := style{font: regular, size: bodySize, colour: black}
+		r.writeLines(wrap(words(v.Nodes, st), width), margin, "", style{})
+		r.gap(bodySize * 0.5)
+
+	case *block.List:
+		r.writeList(v, 0)
+		r.gap(bodySize * 0.5)
+	}
+}
+
+// writeList writes each item with its marker hanging to the left of its text
+// and nested lists indented further.
+func (r *renderer) writeList(l *block.List, depth int) {
+	st := style{font: regular, size: bodySize, colour: black}
+	left := margin + float64(depth)*listIndent
+
+	for _, item := range l.Items {
+		marker := bullets[depth%len(bullets)]
+		if l.Ordered {
+			marker = strconv.Itoa(item.Num) + "."
+		}
+
+		indent := float64(listIndent)
+		if w := regular.width(encode(marker))*bodySize + 4; w > indent {
+			indent = w
+		}
+
+		x := left + indent
+		r.writeLines(wrap(words(item.Nodes, st), pageWidth-margin-x), x, marker, st)
+		r.gap(bodySize * 0.15)
+
+		for _, sub := range item.Lists {
+			r.writeList(sub, depth+1)
+		}
+	}
+}
+
+// writeLines writes wrapped lines of runs starting at 'x'. A 'marker' is
+// written, in style 'ms', to the left of the first line.
+func (r *renderer) writeLines(lines [][]placed, x float64, marker string, ms style) {
+	if len(lines) == 0 {
+		lines = [][]placed{nil} // Empty items still need their marker
+	}
+
+	for i, ln := range lines {
+		size := float64(bodySize)
+		for _, p := range ln {
+			if p.run.style.size > size {
+				size = p.run.style.size
+			}
+		}
+		lh := lineHeight(size)
+
+		r.ensure(lh)
+		baseline := r.y - lh + 0.3*size
+
+		if i == 0 && marker != "" {
+			m := encode(marker)
+			mx := x - regular.width(m)*ms.size - 4
+			r.page.content.WriteString("BT " + ms.font.name() + " " + num(ms.size) + " Tf " + ms.colour + " rg " +
+				num(mx) + " " + num(baseline) + " Td " + literal(m) + " Tj ET\n")
+		}
+
+		r.writeLine(ln, x, baseline)
+		r.y -= lh
+	}
+}
+
+// placed is a run positioned relative to the start of its line.
+type placed struct {
+	x   float64
+	run *run
+}
+
+// writeLine draws highlights behind the text of a line, then the text, then
+// underlines.
+func (r *renderer) writeLine(ln []placed, x, baseline float64) {
+	c := &r.page.content
+	ln = merge(ln)
+
+	for _, p := range ln {
+		if st := p.run.style; st.highlight {
+			c.WriteString(keyBg + " rg " + num(x+p.x) + " " + num(baseline-0.25*st.size) + " " +
+				num(p.run.width) + " " + num(1.15*st.size) + " re f\n")
+		}
+	}
+
+	for _, p := range ln {
+		st := p.run.style
+		c.WriteString("BT " + st.font.name() + " " + num(st.size) + " Tf " + st.colour + " rg " +
+			num(x+p.x) + " " + num(baseline) + " Td " + literal(p.run.text) + " Tj ET\n")
+
+		if st.link != "" {
+			r.page.annots = append(r.page.annots, annot{
+				rect:   [4]float64{x + p.x, baseline - 0.25*st.size, x + p.x + p.run.width, baseline + 0.9*st.size},
+				action: st.link,
+			})
+		}
+	}
+
+	for _, p := range ln {
+		st := p.run.style
+		x1, x2, y := x+p.x, x+p.x+p.run.width, baseline-0.15*st.size
+		switch st.underline {
+		case solidLine:
+			c.WriteString(st.colour + " RG 0.6 w " + num(x1) + " " + num(y) + " m " + num(x2) + " " + num(y) + " l S\n")
+		case wavyLine:
+			c.WriteString(st.colour + " RG 0.5 w " + wave(x1, x2, y) + " S\n")
+		}
+	}
+}
+
+// merge joins adjacent runs of the same style.
+func merge(ln []placed) []placed {
+	r := []placed{}
+	for _, p := range ln {
+		if n := len(r); n > 0 && r[n-1].run.style == p.run.style {
+			prev := r[n-1].run
+			r[n-1].run = &run{text: prev.text + p.run.text, style: p.run.style, width: prev.width + p.run.width}
+			continue
+		}
+		r = append(r, p)
+	}
+	return r
+}
+
+// wave returns a path zigzagging along 'y' from 'x1' to 'x2'.
+func wave(x1, x2, y float64) string {
+	const step, amp = 1.5, 0.7
+
+	sb := strings.Builder{}
+	sb.WriteString(num(x1) + " " + num(y) + " m")
+	up := true
+	for x := x1 + step; x < x2+step; x += step {
+		if x > x2 {
+			x = x2
+		}
+		dy := -amp
+		if up {
+			dy = amp
+		}
+		sb.WriteString(" " + num(x) + " " + num(y+dy) + " l")
+		up = !up
+	}
+	return sb.String()
+}
+
+func lineHeight(size float64) float64 {
+	return size * 1.35
+}
+
+// words splits the text of 'ns' into words of styled runs.
+func words(ns []ast.Node, st style) []*word {
+	b := &builder{}
+	b.writeNodes(ns, st)
+	b.end()
+	return b.words
+}
+
+// builder builds words from the text of nodes.
+type builder struct {
+	words []*word
+	word  *word
+	space *style // Style of the space before the next word
+}
+
+func (b *builder) writeNodes(ns []ast.Node, st style) {
+	for _, n := range ns {
+		b.writeNode(n, st)
+	}
+}
+
+func (b *builder) writeNode(n ast.Node, st style) {
+
+	writeGroup := func(n ast.Node, st style) {
+		if p, ok := n.(ast.Parent); ok {
+			b.writeNodes(p.Nodes(), st)
+		} else {
+			b.text(n.Text(), st)
+		}
+	}
+
+	switch n.Type() {
+	case ast.KeyPhrase:
+		st.highlight = true
+		writeGroup(n, st)
+	case ast.Positive:
+		st.colour, st.underline = positive, solidLine
+		writeGroup(n, st)
+	case ast.Negative:
+		st.colour, st.underline = negative, wavyLine
+		writeGroup(n, st)
+	case ast.Strong:
+		st.font = emphasise(st.font, bold)
+		writeGroup(n, st)
+	case ast.Artifact:
+		st.colour, st.font = artifact, emphasise(st.font, oblique)
+		writeGroup(n, st)
+	case ast.Quote:
+		b.text("“", st)
+		writeGroup(n, st)
+		b.text("”", st)
+	case ast.Snippet:
+		st.font = mono
+		b.text(n.Text(), st)
+	case ast.Tag:
+		st.colour = blue
+		b.text("#"+n.Text(), st)
+	case ast.Link:
+		if l, ok := n.(ast.LinkNode); ok && html.SafeURL(l.URL) {
+			st.colour, st.link = blue, "<< /S /URI /URI "+literal(l.URL)+" >>"
+		}
+		b.text(n.Text(), st)
+	case ast.WikiLink:
+		if l, ok := n.(ast.WikiLinkNode); ok {
+			action := "<< /S /GoToR /F " + literal(encode(ast.Slug(l.Note)+".pdf"))
+			if l.Topic != "" {
+				action += " /D " + name(ast.Slug(l.Topic))
+			} else {
+				action += " /D [0 /Fit]"
+			}
+			st.colour, st.link = blue, action+" >>"
+		}
+		b.text(n.Text(), st)
+	default:
+		writeGroup(n, st)
+	}
+}
+
+// emphasise returns the font combining 'f' with bold or oblique.
+func emphasise(f, with font) font {
+	switch {
+	case f == mono:
+		return mono
+	case f == with, f == boldOblique:
+		return f
+	case f == regular:
+		return with
+	}
+	return boldOblique
+}
+
+// text adds 's' to the current word, starting new words after spaces.
+func (b *builder) text(s string, st style) {
+	for _, ru := range s {
+		if unicode.IsSpace(ru) {
+			b.end()
+			if b.space == nil {
+				sp := st
+				b.space = &sp
+			}
+			continue
+		}
+
+		if b.word == nil {
+			b.word = &word{}
+			if b.space != nil {
+				b.word.space = newRun(" ", *b.space)
+				b.space = nil
+			}
+		}
+
+		rs := b.word.runs
+		if len(rs) > 0 && rs[len(rs)-1].style == st {
+			rs[len(rs)-1].text += encode(string(ru))
+		} else {
+			b.word.runs = append(rs, &run{text: encode(string(ru)), style: st})
+		}
+	}
+}
+
+// end ends the current word.
+func (b *builder) end() {
+	if b.word == nil {
+		return
+	}
+	for _, r := range b.word.runs {
+		r.width = r.style.font.width(r.text) * r.style.size
+		b.word.width += r.width
+	}
+	b.words = append(b.words, b.word)
+	b.word = nil
+}
+
+func newRun(text string, st style) *run {
+	return &run{text: text, style: st, width: st.font.width(text) * st.size}
+}
+
+// wrap breaks words into lines no wider than 'width'. Words wider than a
+// line are broken between characters.
+func wrap(ws []*word, width float64) [][]placed {
+	lines := [][]placed{}
+	var ln []placed
+	x := 0.0
+
+	for _, w := range ws {
+		space := 0.0
+		if len(ln) > 0 && w.space != nil {
+			space = w.space.width
+		}
+
+		if len(ln) > 0 && x+space+w.width > width {
+			lines = append(lines, ln)
+			ln, x, space = nil, 0, 0
+		}
+
+		if len(ln) == 0 && w.width > width {
+			pieces := split(w, width)
+			for _, p := range pieces[:len(pieces)-1] {
+				lines = append(lines, place(nil, 0, p.runs))
+			}
+			w = pieces[len(pieces)-1]
+		}
+
+		if space > 0 {
+			ln = append(ln, placed{x: x, run: w.space})
+			x += space
+		}
+		ln = place(ln, x, w.runs)
+		x += w.width
+	}
+
+	if len(ln) > 0 {
+		lines = append(lines, ln)
+	}
+	return lines
+}
+
+func place(ln []placed, x float64, runs []*run) []placed {
+	for _, r := range runs {
+		ln = append(ln, placed{x: x, run: r})
+		x += r.width
+	}
+	return ln
+}
+
+// split splits a word into pieces no wider than 'width', each holding at
+// least one character.
+func split(w *word, width float64) []*word {
+	pieces := []*word{{}}
+	for _, r := range w.runs {
+		for i := 0; i < len(r.text); i++ {
+			c := newRun(r.text[i:i+1], r.style)
+			p := pieces[len(pieces)-1]
+			if p.width > 0 && p.width+c.width > width {
+				p = &word{}
+				pieces = append(pieces, p)
+			}
+
+			if n := len(p.runs); n > 0 && p.runs[n-1].style == c.style {
+				p.runs[n-1] = newRun(p.runs[n-1].text+c.text, c.style)
+			} else {
+				p.runs = append(p.runs, c)
+			}
+			p.width += c.width
+		}
+	}
+	return pieces
+}
+
+// name returns 's' as a PDF name, escaping characters outside of the
+// printable ASCII range and delimiters.
+func name(s string) string {
+	sb := strings.Builder{}
+	sb.WriteByte('/')
+	for _, b := range []byte(s) {
+		if b <= ' ' || b > '~' || strings.IndexByte("#%()/<>[]{}", b) >= 0 {
+			sb.WriteString("#" + strings.ToUpper(strconv.FormatInt(int64(b), 16)))
+		} else {
+			sb.WriteByte(b)
+		}
+	}
+	return sb.String()
+}
+
+// writeTo writes the pages, fonts, and named destinations as a PDF file.
+func (r *renderer) writeTo(w io.Writer, title string) error {
+	f := &file{}
+	f.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	catalog, info, pages, fonts := f.reserve(), f.reserve(), f.reserve(), f.reserve()
+
+	pageObjs := make([]int, len(r.pages))
+	for i := range r.pages {
+		pageObjs[i] = f.reserve()
+	}
+
+	kids := []string{}
+	for i, p := range r.pages {
+		content := f.reserve()
+		if e := f.stream(content, []byte(p.content.String())); e != nil {
+			return e
+		}
+
+		annots := []string{}
+		for _, a := range p.annots {
+			n := f.reserve()
+			f.object(n, "<< /Type /Annot /Subtype /Link /Border [0 0 0] /Rect ["+
+				num(a.rect[0])+" "+num(a.rect[1])+" "+num(a.rect[2])+" "+num(a.rect[3])+"] /A "+a.action+" >>")
+			annots = append(annots, strconv.Itoa(n)+" 0 R")
+		}
+
+		obj := "<< /Type /Page /Parent " + strconv.Itoa(pages) + " 0 R" +
+			" /MediaBox [0 0 " + num(pageWidth) + " " + num(pageHeight) + "]" +
+			" /Resources << /Font " + strconv.Itoa(fonts) + " 0 R >>" +
+			" /Contents " + strconv.Itoa(content) + " 0 R"
+		if len(annots) > 0 {
+			obj += " /Annots [" + strings.Join(annots, " ") + "]"
+		}
+		f.object(pageObjs[i], obj+" >>")
+		kids = append(kids, strconv.Itoa(pageObjs[i])+" 0 R")
+	}
+
+	fontRefs := []string{}
+	for i, base := range baseFonts {
+		n := f.reserve()
+		f.object(n, "<< /Type /Font /Subtype /Type1 /BaseFont /"+base+" /Encoding /WinAnsiEncoding >>")
+		fontRefs = append(fontRefs, font(i).name()+" "+strconv.Itoa(n)+" 0 R")
+	}
+	f.object(fonts, "<< "+strings.Join(fontRefs, " ")+" >>")
+
+	f.object(pages, "<< /Type /Pages /Kids ["+strings.Join(kids, " ")+"] /Count "+strconv.Itoa(len(kids))+" >>")
+	f.object(info, "<< /Title "+textString(title)+" >>")
+
+	dests := []string{}
+	for _, n := range r.names {
+		d := r.dests[n]
+		dests = append(dests, name(n)+" ["+strconv.Itoa(pageObjs[d.page])+" 0 R /XYZ null "+num(d.y)+" null]")
+	}
+	f.object(catalog, "<< /Type /Catalog /Pages "+strconv.Itoa(pages)+" 0 R /Dests << "+strings.Join(dests, " ")+" >> >>")
+
+	return f.writeTo(w, catalog, info)
+}
@@ -0,0 +1,135 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+// check verifies the cross reference table of the PDF file 'b' and returns
+// its objects with their streams inflated.
+func check(t *testing.T, b []byte) string {
+	s := string(b)
+	require.True(t, strings.HasPrefix(s, "%PDF-1.4\n"))
+	require.True(t, strings.HasSuffix(s, "%%EOF\n"))
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(s)
+	require.NotNil(t, m)
+	xref, _ := strconv.Atoi(m[1])
+	require.True(t, strings.HasPrefix(s[xref:], "xref\n0 "))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(s[xref:], -1)
+	require.NotEmpty(t, entries)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(e[1])
+		require.True(t, strings.HasPrefix(s[off:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+
+	sb := strings.Builder{}
+	streams := regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`)
+	last := 0
+	for _, loc := range streams.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(s[last:loc[2]])
+		z, e := zlib.NewReader(bytes.NewReader(b[loc[2]:loc[3]]))
+		require.Nil(t, e)
+		data, e := ioutil.ReadAll(z)
+		require.Nil(t, e)
+		sb.Write(data)
+		last = loc[3]
+	}
+	sb.WriteString(s[last:])
+	return sb.String()
+}
+
+func TestRender_1(t *testing.T) {
+
+	in := `# Cheese & Wine
+## Types
+. **Chedder** from $Somerset
+.. Mild and *mature*, see ` + "`man cheese`" + `
+!3 Curdling
+-Smelly- but +tasty+ [[Wine#Reds]] #dairy
+See [shop](https://a.org/c) not [this](javascript:x)`
+
+	b, e := Render(parser.ParseAll(scanner.ScanAll(in)))
+	require.Nil(t, e)
+	s := check(t, b)
+
+	require.Contains(t, s, "/Title <FEFF00430068006500650073006500200026002000570069006E0065>")
+	require.Contains(t, s, "/Type /Pages /Kids [5 0 R] /Count 1")
+	require.Contains(t, s, "/BaseFont /Helvetica /Encoding /WinAnsiEncoding")
+	require.Contains(t, s, "/BaseFont /Courier /Encoding /WinAnsiEncoding")
+	require.Contains(t, s, "/Dests << /cheese-wine [5 0 R /XYZ null 785.2 null] /types [5 0 R")
+
+	require.Contains(t, s, "/F2 20 Tf 0.122 0.22 0.392 rg 56.69 764.2 Td (Cheese & Wine) Tj ET")
+	require.Contains(t, s, "/F2 16 Tf 0.122 0.22 0.392 rg")
+	require.Contains(t, s, "/F1 11 Tf 0 0 0 rg 56.69 ")
+	require.Contains(t, s, "(\\225) Tj ET")              // Bullet
+	require.Contains(t, s, "(\\226) Tj ET")              // Nested bullet
+	require.Contains(t, s, "(3.) Tj ET")                 // Number
+	require.Contains(t, s, "1 0.953 0.627 rg")           // Key phrase highlight
+	require.Contains(t, s, "/F2 11 Tf 0 0 0 rg")         // Strong
+	require.Contains(t, s, "/F3 11 Tf 0.4 0.224 0.729")  // Artifact
+	require.Contains(t, s, "/F5 11 Tf")                  // Snippet
+	require.Contains(t, s, "0.812 0.133 0.18 RG 0.5 w")  // Wavy underline
+	require.Contains(t, s, "0.102 0.498 0.216 RG 0.6 w") // Solid underline
+	require.Contains(t, s, "/A << /S /URI /URI (https://a.org/c) >>")
+	require.Contains(t, s, "/A << /S /GoToR /F (wine.pdf) /D /reds >>")
+	require.NotContains(t, s, "javascript")
+}
+
+func TestRender_2(t *testing.T) {
+	in := strings.Repeat("A paragraph of words that goes on for a while. ", 12)
+	in = strings.Repeat(in+"\n\n", 40)
+
+	b, e := Render(parser.ParseAll(scanner.ScanAll(in)))
+	require.Nil(t, e)
+	s := check(t, b)
+
+	m := regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(s)
+	require.NotNil(t, m)
+	pages, _ := strconv.Atoi(m[1])
+	require.True(t, pages > 1, "expected several pages, got %d", pages)
+	require.Contains(t, s, "(2) Tj ET") // Page number
+}
+
+func TestWrap_1(t *testing.T) {
+	st := style{font: regular, size: 10, colour: black}
+	b := &builder{}
+	b.text("aaa bbb  ccc "+strings.Repeat("d", 40), st)
+	b.end()
+
+	lines := wrap(b.words, 60)
+	for _, ln := range lines {
+		last := ln[len(ln)-1]
+		require.True(t, last.x+last.run.width <= 60.001)
+	}
+
+	text := []string{}
+	for _, ln := range lines {
+		sb := strings.Builder{}
+		for _, p := range ln {
+			sb.WriteString(p.run.text)
+		}
+		text = append(text, sb.String())
+	}
+	require.Equal(t, []string{"aaa bbb ccc", "dddddddddd", "dddddddddd", "dddddddddd", "dddddddddd"}, text)
+}
+
+func TestEncode_1(t *testing.T) {
+	require.Equal(t, "\x93\xe9\x80?\x94 -", encode("“é€中” -"))
+	require.Equal(t, `(a\(b\)\\\223)`, literal("a(b)\\\x93"))
+	require.Equal(t, "/caf#C3#A9#2Fx", name("café/x"))
+	require.Equal(t, "1.5", num(1.5))
+	require.Equal(t, "2", num(2.001))
+}
@@ -0,0 +1,99 @@
+package preview
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// keepAlive is how often a comment is sent to idle event streams so proxies
+// and browsers don't close them.
+const keepAlive = 15 * time.Second
+
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	c := s.subscribe()
+	defer s.unsubscribe(c)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, "retry: 1000\n\n")
+	f.Flush()
+
+	t := time.NewTicker(keepAlive)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-t.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case path := <-c:
+			fmt.Fprintf(w, "event: change\ndata: %s\n\n", path)
+		}
+		f.Flush()
+	}
+}
+
+// script returns the script that refreshes the page showing the note at
+// 'path', or the index if empty, when it changes. Before the content is
+// replaced, the source line of the first visible element and its offset are
+// noted so the same line can be scrolled back into place afterwards. A page
+// URL ending in '#L12' scrolls to line 12 when loaded.
+func script(path string) string {
+	js, _ := json.Marshal(path)
+	return `<script>
+(function() {
+  var path = ` + string(js) + `;
+  var main = document.getElementById("dw-content");
+
+  function lines() {
+    return main.querySelectorAll("[data-line]");
+  }
+
+  function firstVisible() {
+    var els = lines();
+    for (var i = 0; i < els.length; i++) {
+      var top = els[i].getBoundingClientRect().top;
+      if (top >= 0) {
+        return {line: +els[i].dataset.line, offset: top};
+      }
+    }
+    return null;
+  }
+
+  function scrollToLine(line, offset) {
+    var els = lines(), best = null;
+    for (var i = 0; i < els.length; i++) {
+      if (+els[i].dataset.line > line) break;
+      best = els[i];
+    }
+    if (best) {
+      window.scrollTo(0, best.getBoundingClientRect().top + window.pageYOffset - offset);
+    }
+  }
+
+  new EventSource("/events").addEventListener("change", function(e) {
+    if (path !== "" && e.data !== path) return;
+    var pos = firstVisible();
+    fetch("/fragment/" + encodeURI(path)).then(function(res) {
+      return res.ok ? res.text() : "<p>This note no longer exists.</p>";
+    }).then(function(html) {
+      main.innerHTML = html;
+      if (pos) scrollToLine(pos.line, pos.offset);
+    });
+  });
+
+  var m = location.hash.match(/^#L(\d+)$/);
+  if (m) scrollToLine(+m[1], 0);
+})();
+</script>
+`
+}
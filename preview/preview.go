@@ -0,0 +1,274 @@
+// Package preview serves notes as HTML for previewing while they are edited.
+// Pages refresh themselves when their notes change: changes are pushed to
+// browsers using Server-Sent Events and the scroll position is kept by
+// mapping the source lines of rendered elements, see html.Options.DataLines.
+package preview
+
+import (
+	gohtml "html"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/wiki"
+)
+
+// Server is an http.Handler serving a note or the notes of a notebook. Its
+// routes are:
+//   - '/' the note, or an index of the notebook's notes
+//   - '/note/{path}' a note
+//   - '/fragment/{path}' the HTML of a note without the page around it, the
+//     index if the path is empty
+//   - '/events' a stream of Server-Sent Events named 'change' whose data is
+//     the path of the changed note
+type Server struct {
+	nb   *notebook.Notebook
+	file string // Path of the note if serving a single note
+
+	mu      sync.Mutex
+	clients map[chan string]bool
+	stamps  map[string]stamp
+}
+
+// stamp identifies a version of a file.
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// New creates a Server for the note file or notebook directory at 'path'.
+func New(path string) (*Server, error) {
+	info, e := os.Stat(path)
+	if e != nil {
+		return nil, e
+	}
+
+	root, file := path, ""
+	if !info.IsDir() {
+		root, file = filepath.Dir(path), filepath.Base(path)
+	}
+
+	nb, e := notebook.Open(root)
+	if e != nil {
+		return nil, e
+	}
+
+	s := &Server{
+		nb:      nb,
+		file:    filepath.ToSlash(file),
+		clients: map[chan string]bool{},
+	}
+	s.stamps, e = s.scan()
+	return s, e
+}
+
+// Watch polls for changed notes every 'interval' until 'stop' is closed.
+func (s *Server) Watch(interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			s.Poll()
+		}
+	}
+}
+
+// Poll notifies clients of the notes that were added, changed, or removed
+// since the last poll.
+func (s *Server) Poll() error {
+	stamps, e := s.scan()
+	if e != nil {
+		return e
+	}
+
+	s.mu.Lock()
+	old := s.stamps
+	s.stamps = stamps
+	s.mu.Unlock()
+
+	for p, st := range stamps {
+		if prev, ok := old[p]; !ok || prev != st {
+			s.notify(p)
+		}
+	}
+	for p := range old {
+		if _, ok := stamps[p]; !ok {
+			s.notify(p)
+		}
+	}
+	return nil
+}
+
+func (s *Server) scan() (map[string]stamp, error) {
+	paths, e := s.paths()
+	if e != nil {
+		return nil, e
+	}
+
+	r := make(map[string]stamp, len(paths))
+	for _, p := range paths {
+		info, e := os.Stat(filepath.Join(s.nb.Root(), filepath.FromSlash(p)))
+		if e == nil {
+			r[p] = stamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return r, nil
+}
+
+// paths returns the paths of the notes being served.
+func (s *Server) paths() ([]string, error) {
+	if s.file != "" {
+		return []string{s.file}, nil
+	}
+	return s.nb.Paths()
+}
+
+func (s *Server) notify(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		select {
+		case c <- path:
+		default: // Client is behind so will catch up with a later change
+		}
+	}
+}
+
+func (s *Server) subscribe() chan string {
+	c := make(chan string, 16)
+	s.mu.Lock()
+	s.clients[c] = true
+	s.mu.Unlock()
+	return c
+}
+
+func (s *Server) unsubscribe(c chan string) {
+	s.mu.Lock()
+	delete(s.clients, c)
+	s.mu.Unlock()
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch p := r.URL.Path; {
+	case p == "/events":
+		s.serveEvents(w, r)
+	case p == "/" && s.file != "":
+		s.servePage(w, s.file)
+	case p == "/":
+		s.servePage(w, "")
+	case strings.HasPrefix(p, "/note/"):
+		s.servePage(w, strings.TrimPrefix(p, "/note/"))
+	case strings.HasPrefix(p, "/fragment/"):
+		s.serveFragment(w, strings.TrimPrefix(p, "/fragment/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) servePage(w http.ResponseWriter, path string) {
+	title, body, ok := s.render(path)
+	if !ok {
+		http.Error(w, "no note at "+path, http.StatusNotFound)
+		return
+	}
+
+	page := `<main id="dw-content">` + "\n" + body + "</main>\n" + script(path)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(html.Document(title, page)))
+}
+
+func (s *Server) serveFragment(w http.ResponseWriter, path string) {
+	_, body, ok := s.render(path)
+	if !ok {
+		http.Error(w, "no note at "+path, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(body))
+}
+
+// render returns the title and HTML of the note at 'path', or of the index
+// if 'path' is empty. False is returned if there is no such note.
+func (s *Server) render(path string) (string, string, bool) {
+	paths, e := s.paths()
+	if e != nil {
+		return "", "", false
+	}
+
+	if path == "" {
+		return "Notes", index(paths), true
+	}
+
+	if !contains(paths, path) {
+		return "", "", false // Also prevents paths escaping the root
+	}
+
+	n, e := s.nb.Note(path)
+	if e != nil {
+		return "", "", false
+	}
+
+	body := html.FragmentWith(n.Notes, html.Options{
+		WikiHref:  s.wikiHref(path, paths),
+		DataLines: true,
+	})
+	return n.Meta.Title, body, true
+}
+
+// wikiHref returns a function linking wiki links within the note at 'from'
+// to the pages of the notes they resolve to.
+func (s *Server) wikiHref(from string, paths []string) func(ast.WikiLinkNode) (string, bool) {
+	notes := []wiki.Note{}
+	for _, p := range paths {
+		if n, e := s.nb.Note(p); e == nil {
+			notes = append(notes, wiki.Note{Path: p, Notes: n.Notes})
+		}
+	}
+	res := wiki.NewResolver(notes)
+
+	return func(l ast.WikiLinkNode) (string, bool) {
+		link := res.Resolve(from, l)
+		if link.Broken() {
+			return "", false
+		}
+
+		href := (&url.URL{Path: "/note/" + link.To}).EscapedPath()
+		if link.Anchor != "" {
+			href += "#" + link.Anchor
+		}
+		return href, true
+	}
+}
+
+func index(paths []string) string {
+	sb := &strings.Builder{}
+	sb.WriteString("<h1>Notes</h1>\n<ul>\n")
+	for _, p := range paths {
+		href := (&url.URL{Path: "/note/" + p}).EscapedPath()
+		sb.WriteString(`<li><a href="` + gohtml.EscapeString(href) + `">`)
+		sb.WriteString(gohtml.EscapeString(p) + "</a></li>\n")
+	}
+	sb.WriteString("</ul>\n")
+	return sb.String()
+}
+
+func contains(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
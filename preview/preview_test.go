@@ -0,0 +1,102 @@
+package preview
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tempDir(t *testing.T) (string, func()) {
+	root, e := ioutil.TempDir("", "preview")
+	require.Nil(t, e)
+
+	write(t, root, "cheese.dw", "# Cheese\n. [[Curds]]")
+	write(t, root, "curds.dw", "# Curds")
+	return root, func() { os.RemoveAll(root) }
+}
+
+func write(t *testing.T, root, path, s string) {
+	require.Nil(t, ioutil.WriteFile(filepath.Join(root, path), []byte(s), 0644))
+}
+
+func get(t *testing.T, s *Server, path string) (int, string) {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w.Code, w.Body.String()
+}
+
+func TestServer_1(t *testing.T) {
+	root, cleanup := tempDir(t)
+	defer cleanup()
+
+	s, e := New(root)
+	require.Nil(t, e)
+
+	code, body := get(t, s, "/")
+	require.Equal(t, 200, code)
+	require.Contains(t, body, `<a href="/note/cheese.dw">cheese.dw</a>`)
+
+	code, body = get(t, s, "/note/cheese.dw")
+	require.Equal(t, 200, code)
+	require.Contains(t, body, `<h1 id="cheese" data-line="1">Cheese</h1>`)
+	require.Contains(t, body, `<a class="dw-wiki-link" href="/note/curds.dw">Curds</a>`)
+	require.Contains(t, body, `var path = "cheese.dw";`)
+
+	code, body = get(t, s, "/fragment/curds.dw")
+	require.Equal(t, 200, code)
+	require.Equal(t, `<h1 id="curds" data-line="1">Curds</h1>`+"\n", body)
+
+	code, _ = get(t, s, "/note/../preview_test.go")
+	require.Equal(t, 404, code)
+}
+
+func TestServer_2(t *testing.T) {
+	root, cleanup := tempDir(t)
+	defer cleanup()
+
+	s, e := New(filepath.Join(root, "curds.dw"))
+	require.Nil(t, e)
+
+	_, body := get(t, s, "/")
+	require.Contains(t, body, `<h1 id="curds" data-line="1">Curds</h1>`)
+
+	code, _ := get(t, s, "/note/cheese.dw")
+	require.Equal(t, 404, code)
+}
+
+func TestEvents_1(t *testing.T) {
+	root, cleanup := tempDir(t)
+	defer cleanup()
+
+	s, e := New(root)
+	require.Nil(t, e)
+
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	res, e := http.Get(ts.URL + "/events")
+	require.Nil(t, e)
+	defer res.Body.Close()
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(res.Body)
+	line, e := r.ReadString('\n')
+	require.Nil(t, e)
+	require.Equal(t, "retry: 1000\n", line)
+
+	write(t, root, "curds.dw", "# Curds\n. Cut")
+	require.Nil(t, s.Poll())
+
+	for !strings.HasPrefix(line, "data:") {
+		line, e = r.ReadString('\n')
+		require.Nil(t, e)
+	}
+	require.Equal(t, "data: curds.dw\n", line)
+}
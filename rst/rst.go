@@ -0,0 +1,360 @@
+// Package rst renders parsed notes as reStructuredText, e.g. for Sphinx.
+//
+// Topics become sections, underlined with '=', '-', '~', '^', '"', and then
+// "'" for each level in turn. Skipped levels are closed up, see
+// block.Normalise, since reStructuredText rejects them. Lists become bullet
+// and enumerated lists. reStructuredText has no equivalent for some Daft
+// Wullie phrases so the following conventions are used:
+//   - strong phrases become strong, '**strong**', key phrases become
+//     emphasis, '*emphasis*', and snippets become inline literals
+//   - positive, negative, and artifact phrases become the custom roles
+//     'dw-positive', 'dw-negative', and 'dw-artifact', declared by Header,
+//     so they are given the same classes as the html package uses
+//   - quotes are wrapped in double quotes
+//   - wiki links become links to the HTML file named after the slug of the
+//     note, as html.DefaultWikiHref
+//   - links with unsafe schemes, see html.SafeURL, are rendered as text
+//
+// reStructuredText cannot nest inline markup so phrases within phrases are
+// rendered as plain text.
+package rst
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+)
+
+// Header declares the custom roles phrases are rendered as.
+const Header = `.. role:: dw-positive
+.. role:: dw-negative
+.. role:: dw-artifact
+`
+
+// adornments are the characters underlining each level of section.
+const adornments = `=-~^"'`
+
+// enumerator matches the start of a line that would be read as an item of
+// an enumerated list, e.g. '1. ', 'a) ', or '(iv) '.
+var enumerator = regexp.MustCompile(`^(?:[\w#]+[.)]|\(\w+\))(?:\s|$)`)
+
+// Render renders 'notes' as a reStructuredText document starting with the
+// Header.
+func Render(notes ast.Notes) string {
+	return Header + "\n" + Fragment(notes)
+}
+
+// Fragment renders 'notes' as reStructuredText without the Header.
+func Fragment(notes ast.Notes) string {
+	sb := &strings.Builder{}
+	for _, b := range block.Normalise(block.Build(notes)) {
+		if _, ok := b.(block.Break); ok {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		writeBlock(sb, b)
+	}
+	return sb.String()
+}
+
+func writeBlock(sb *strings.Builder, b block.Block) {
+	switch v := b.(type) {
+	case block.Heading:
+		s := escapeLineEnd(Inline(v.Nodes))
+		if s == "" {
+			s = "\\ " // Titles may not be empty
+		}
+		level := v.Level
+		if level > len(adornments) {
+			level = len(adornments)
+		}
+		sb.WriteString(s + "\n")
+		sb.WriteString(strings.Repeat(string(adornments[level-1]), width(s)) + "\n")
+
+	case block.Para:
+		sb.WriteString(escapeLineEnd(Inline(v.Nodes)))
+		sb.WriteString("\n")
+
+	case *block.List:
+		writeList(sb, v, "")
+	}
+}
+
+// writeList writes a list. Nested lists are indented to the text of their
+// parent item and surrounded by blank lines.
+func writeList(sb *strings.Builder, l *block.List, indent string) {
+	for i, item := range l.Items {
+		if i > 0 && (len(l.Items[i-1].Lists) > 0 || l.Ordered && item.Start > 0) {
+			sb.WriteString("\n") // Ends a nested list or starts a new one
+		}
+
+		marker := "- "
+		if l.Ordered {
+			marker = strconv.Itoa(item.Num) + ". "
+		}
+
+		sb.WriteString(indent + marker)
+		sb.WriteString(escapeLineEnd(Inline(item.Nodes)))
+		sb.WriteString("\n")
+
+		for _, sub := range item.Lists {
+			sb.WriteString("\n")
+			writeList(sb, sub, indent+strings.Repeat(" ", len(marker)))
+		}
+	}
+}
+
+// Inline renders a sequence of phrase nodes as inline reStructuredText. Text
+// at the start is escaped if it would otherwise be read as block markup, e.g.
+// a list item or section adornment.
+func Inline(ns []ast.Node) string {
+	w := &writer{}
+	for _, n := range ns {
+		w.writeNode(n)
+	}
+	return w.sb.String()
+}
+
+// writer writes inline markup, separating it from adjacent text with escaped
+// spaces where reStructuredText would otherwise not recognise it.
+type writer struct {
+	sb     strings.Builder
+	last   rune // Last rune written, zero at the start
+	closed bool // Inline markup was just written
+}
+
+func (w *writer) writeNode(n ast.Node) {
+	switch n.Type() {
+	case ast.KeyPhrase:
+		w.markup("*", Escape(flatten(n)), "*")
+	case ast.Positive:
+		w.markup(":dw-positive:`", Escape(flatten(n)), "`")
+	case ast.Negative:
+		w.markup(":dw-negative:`", Escape(flatten(n)), "`")
+	case ast.Strong:
+		w.markup("**", Escape(flatten(n)), "**")
+	case ast.Artifact:
+		w.markup(":dw-artifact:`", Escape(flatten(n)), "`")
+	case ast.Quote:
+		w.text(`"`)
+		if p, ok := n.(ast.Parent); ok {
+			for _, c := range p.Nodes() {
+				w.writeNode(c)
+			}
+		}
+		w.text(`"`)
+	case ast.Snippet:
+		w.writeSnippet(n.Text())
+	case ast.Link:
+		w.writeLink(n)
+	case ast.WikiLink:
+		w.writeWikiLink(n)
+	case ast.Tag:
+		w.text(Escape("#" + n.Text()))
+	default:
+		if p, ok := n.(ast.Parent); ok {
+			for _, c := range p.Nodes() {
+				w.writeNode(c)
+			}
+		} else {
+			w.text(Escape(n.Text()))
+		}
+	}
+}
+
+// writeSnippet writes 's' as an inline literal or, if it contains a pair of
+// backquotes, as a literal role since inline literals cannot be escaped.
+func (w *writer) writeSnippet(s string) {
+	if strings.Contains(s, "``") || strings.HasSuffix(s, "`") {
+		w.markup(":literal:`", Escape(s), "`")
+		return
+	}
+	w.markup("``", s, "``")
+}
+
+func (w *writer) writeLink(n ast.Node) {
+	l, ok := n.(ast.LinkNode)
+	switch {
+	case !ok, !html.SafeURL(l.URL):
+		w.text(Escape(n.Text()))
+	case l.Label == "":
+		w.markup("`", "<"+escapeURL(l.URL)+">", "`__")
+	default:
+		w.markup("`", escapeLabel(l.Label)+" <"+escapeURL(l.URL)+">", "`__")
+	}
+}
+
+// writeWikiLink writes a wiki link as a link to the HTML file named after
+// the slug of the note.
+func (w *writer) writeWikiLink(n ast.Node) {
+	l, ok := n.(ast.WikiLinkNode)
+	if !ok {
+		w.text(Escape(n.Text()))
+		return
+	}
+
+	href, _ := html.DefaultWikiHref(l)
+	w.markup("`", escapeLabel(l.Text())+" <"+escapeURL(href)+">", "`__")
+}
+
+// markup writes inline markup with the 'content' between 'start' and 'end'.
+// Whitespace at either end of the content is moved outside the markup.
+func (w *writer) markup(start, content, end string) {
+	trimmed := strings.TrimLeftFunc(content, unicode.IsSpace)
+	w.text(content[:len(content)-len(trimmed)])
+	content = trimmed
+
+	trimmed = strings.TrimRightFunc(content, unicode.IsSpace)
+	after := content[len(trimmed):]
+	content = trimmed
+
+	if content != "" {
+		if w.last != 0 && !unicode.IsSpace(w.last) && !strings.ContainsRune(`-:/'"<([{`, w.last) {
+			w.sb.WriteString(`\ `)
+		}
+		w.sb.WriteString(start + content + end)
+		w.last, w.closed = '`', true
+	}
+
+	w.text(after)
+}
+
+// text writes escaped text.
+func (w *writer) text(s string) {
+	if s == "" {
+		return
+	}
+
+	if w.last == 0 {
+		if s = strings.TrimLeftFunc(s, unicode.IsSpace); s == "" {
+			return // Indented lines are block quotes
+		}
+		s = escapeLineStart(s)
+	}
+
+	first := []rune(s)[0]
+	if w.closed && !unicode.IsSpace(first) && !strings.ContainsRune(`-.,:;!?\/'")]}>`, first) {
+		w.sb.WriteString(`\ `)
+	}
+
+	w.sb.WriteString(s)
+	w.last, w.closed = []rune(s)[len([]rune(s))-1], false
+}
+
+// flatten returns the text of 'n' and its descendants without markup, other
+// than the marks of quotes which are part of the text.
+func flatten(n ast.Node) string {
+	switch n.Type() {
+	case ast.Tag:
+		return "#" + n.Text()
+	case ast.Link, ast.WikiLink:
+		return n.Text()
+	}
+
+	p, ok := n.(ast.Parent)
+	if !ok {
+		return n.Text()
+	}
+
+	sb := strings.Builder{}
+	for _, c := range p.Nodes() {
+		sb.WriteString(flatten(c))
+	}
+
+	if n.Type() == ast.Quote {
+		return `"` + sb.String() + `"`
+	}
+	return sb.String()
+}
+
+// Escape escapes characters within 's' that would otherwise start inline
+// markup, references, or substitutions, and URLs that would otherwise be
+// recognised as links.
+func Escape(s string) string {
+	sb := strings.Builder{}
+	for i, ru := range s {
+		switch {
+		case strings.ContainsRune("\\*`_|", ru):
+			sb.WriteRune('\\')
+		case ru == ':' && isScheme(s[:i]):
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(ru)
+	}
+	return sb.String()
+}
+
+// escapeLineStart escapes the start of a line that would otherwise be read
+// as a list item, directive, section adornment, or other block markup.
+func escapeLineStart(s string) string {
+	if enumerator.MatchString(s) {
+		i := strings.IndexAny(s, ".)")
+		return s[:i] + `\` + s[i:]
+	}
+
+	first := []rune(s)[0]
+	if first != '\\' && (unicode.IsPunct(first) || unicode.IsSymbol(first)) {
+		return `\` + s
+	}
+	return s
+}
+
+// escapeLineEnd escapes a '::' at the end of a line that would otherwise
+// start a literal block.
+func escapeLineEnd(s string) string {
+	if strings.HasSuffix(s, "::") && !strings.HasSuffix(s, `\::`) {
+		return s[:len(s)-1] + `\:`
+	}
+	return s
+}
+
+// escapeLabel escapes the label of a link.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "`", "\\`", "<", `\<`).Replace(s)
+}
+
+// escapeURL percent encodes characters that cannot appear in the target of
+// a link.
+func escapeURL(s string) string {
+	return strings.NewReplacer(
+		" ", "%20",
+		"<", "%3C",
+		">", "%3E",
+		"`", "%60",
+		`\`, "%5C",
+	).Replace(s)
+}
+
+// isScheme returns true if 's' ends with a URL scheme that reStructuredText
+// recognises in text.
+func isScheme(s string) bool {
+	for _, scheme := range []string{"http", "https", "ftp", "mailto", "file"} {
+		if strings.HasSuffix(s, scheme) {
+			rest := s[:len(s)-len(scheme)]
+			if rest == "" || !unicode.IsLetter([]rune(rest)[len([]rune(rest))-1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// width returns the number of columns 's' occupies, counting wide East Asian
+// characters as two.
+func width(s string) int {
+	n := 0
+	for _, ru := range s {
+		n++
+		if unicode.In(ru, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,77 @@
+package rst
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFragment_1(t *testing.T) {
+
+	in := `# Cheese
+### Types
+. Chedder from $Somerset
+.. Mild *and* mature, see ` + "`man cheese`" + `
+. Brie
+-Smelly- but **ripe** and +tasty+ [[Wine#Reds]]
+See [shop](https://a.org/c) not [this](javascript:x)
+Con*cat*enated and +with **nested** key+
+\- not a list, -negative-
+1. not numbered
+
+!3 Curdling
+! Ripening
+!1 Salting`
+
+	exp := `Cheese
+======
+
+Types
+-----
+
+- Chedder from :dw-artifact:` + "`Somerset`" + `
+
+  - Mild **and** mature, see ` + "``man cheese``" + `
+
+- Brie
+
+:dw-negative:` + "`Smelly`" + ` but *ripe* and :dw-positive:` + "`tasty`" + ` ` + "`Wine#Reds <wine.html#reds>`__" + `
+
+See ` + "`shop <https://a.org/c>`__" + ` not this
+
+Con\ **cat**\ enated and :dw-positive:` + "`with nested key`" + `
+
+\- not a list, :dw-negative:` + "`negative`" + `
+
+1\. not numbered
+
+3. Curdling
+4. Ripening
+
+1. Salting
+`
+
+	act := Fragment(parser.ParseAll(scanner.ScanAll(in)))
+	require.Equal(t, exp, act)
+}
+
+func TestRender_1(t *testing.T) {
+	act := Render(parser.ParseAll(scanner.ScanAll("# 漢字 *x*")))
+	require.True(t, strings.HasPrefix(act, Header+"\n"))
+	require.True(t, strings.HasSuffix(act, "\n漢字 \\*x\\*\n==========\n"))
+}
+
+func TestEscape_1(t *testing.T) {
+	require.Equal(t, `a\_b \*c\* \`+"`d\\`"+` \|e\| \\ see https\://x`, Escape(`a_b *c* `+"`d`"+` |e| \ see https://x`))
+	require.Equal(t, `\* x`, escapeLineStart(`* x`))
+	require.Equal(t, `1\. x`, escapeLineStart(`1. x`))
+	require.Equal(t, `(a\) x`, escapeLineStart(`(a) x`))
+	require.Equal(t, `See:\:`, escapeLineEnd(`See::`))
+	require.Equal(t, ":literal:`a\\`\\`b`", Inline([]ast.Node{ast.MakeSnippet("a``b")}))
+	require.Equal(t, ":dw-positive:`1 \"q\"`", Inline([]ast.Node{ast.MakePositive(ast.MakeText("1 "), ast.MakeQuote(ast.MakeText("q")))}))
+}
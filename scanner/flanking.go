@@ -0,0 +1,246 @@
+package scanner
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PaulioRandall/daft-wullie-go/token"
+)
+
+// applyFlanking converts '+', '-', and '*' symbol tokens into text tokens
+// when they appear within words or numbers, or cannot otherwise open or close
+// a phrase. The rules are similar, but not identical, to the CommonMark rules
+// for emphasis delimiter runs.
+//
+// The following are some experimental documentation formats:
+//
+// Descriptive definition of behaviour:
+//   - a run is a series of symbols made from the same character, e.g. '++'
+//   - the runes either side of a run decide what each symbol in it may do
+//   - the start and end of a line count as whitespace
+//   - a run within a word or number, e.g. 'well-known', is always text
+//   - a run is left flanking if it is not followed by whitespace and either
+//     it is not followed by punctuation or it is preceded by whitespace or
+//     punctuation
+//   - a run is right flanking if it is not preceded by whitespace and either
+//     it is not preceded by punctuation or it is followed by whitespace or
+//     punctuation
+//   - a right flanking run closes as many open phrases as it can, innermost
+//     first, e.g. '***' closes a strong phrase then a key phrase
+//   - a left flanking run then opens as many phrases as it can, as long as each
+//     differs from the innermost open phrase
+//   - all remaining symbols in a run are converted to text
+//   - quotes and artifacts open and close as they always do
+//   - links, wiki links, and tags neither open nor close phrases
+//   - symbols within snippets and symbols following an escape token are
+//     left untouched
+//
+// Axiomatic definition of behaviour:
+// - SYM := POSITIVE | NEGATIVE | STRONG | KEY_PHRASE
+// - WORD SYM WORD                       -> WORD Text(SYM) WORD
+// - RIGHT_FLANKING SYM, innermost == SYM -> SYM (closes)
+// - LEFT_FLANKING SYM,  innermost != SYM -> SYM (opens)
+// - otherwise SYM                       -> Text(SYM)
+func applyFlanking(in []token.Lexeme) []token.Lexeme {
+
+	size := len(in)
+	out := make([]token.Lexeme, 0, size)
+
+	open := []token.Token{}
+	inSnippet := false
+
+	innermost := func() token.Token {
+		if len(open) == 0 {
+			return token.Undefined
+		}
+		return open[len(open)-1]
+	}
+
+	toggle := func(tk token.Token) {
+		if innermost() == tk {
+			open = open[:len(open)-1]
+		} else {
+			open = append(open, tk)
+		}
+	}
+
+	for i := 0; i < size; i++ {
+		lx := in[i]
+
+		switch {
+		case lx.Token == token.Escape:
+			out = append(out, lx)
+			if i+1 < size {
+				i++ // Leave the escaped lexeme untouched
+				out = append(out, in[i])
+			}
+			continue
+
+		case lx.Token == token.Snippet:
+			inSnippet = !inSnippet
+			out = append(out, lx)
+			continue
+
+		case inSnippet, lx.Token == token.Text:
+			out = append(out, lx)
+			continue
+
+		case lx.Token == token.Quote, lx.Token == token.Artifact:
+			toggle(lx.Token)
+			out = append(out, lx)
+			continue
+
+		case !isFlankable(lx.Token):
+			out = append(out, lx) // Links, wiki links, and tags
+			continue
+		}
+
+		end := runEnd(in, i)
+		prev, next := lastRuneOf(in, i-1), firstRuneOf(in, end)
+		sym, _ := utf8.DecodeRuneInString(lx.Val)
+		rest := runLength(in[i:end])
+
+		if isWordRune(prev) && isWordRune(next) {
+			out = append(out, repeatSym(token.Text, sym, rest))
+			i = end - 1
+			continue
+		}
+
+		// Close as many open phrases as the run allows
+		for isRightFlanking(prev, next) && rest > 0 {
+			tk := innermost()
+			n := symbolWidth(tk, sym)
+			if n == 0 || n > rest {
+				break
+			}
+			open = open[:len(open)-1]
+			out = append(out, repeatSym(tk, sym, n))
+			rest -= n
+		}
+
+		// Then open as many new phrases as the run allows
+		for isLeftFlanking(prev, next) && rest > 0 {
+			tk := symbolToken(sym, rest)
+			n := symbolWidth(tk, sym)
+			if innermost() == tk {
+				break
+			}
+			open = append(open, tk)
+			out = append(out, repeatSym(tk, sym, n))
+			rest -= n
+		}
+
+		if rest > 0 {
+			out = append(out, repeatSym(token.Text, sym, rest))
+		}
+
+		i = end - 1
+	}
+
+	return out
+}
+
+// symbolToken returns the token a run of 'n' 'sym' characters starts with.
+func symbolToken(sym rune, n int) token.Token {
+	switch {
+	case sym == '+':
+		return token.Positive
+	case sym == '-':
+		return token.Negative
+	case n > 1:
+		return token.KeyPhrase
+	default:
+		return token.Strong
+	}
+}
+
+// symbolWidth returns the number of 'sym' characters that make up the
+// symbol for 'tk' or zero if 'tk' is not made from 'sym'.
+func symbolWidth(tk token.Token, sym rune) int {
+	switch {
+	case tk == token.Positive && sym == '+':
+		return 1
+	case tk == token.Negative && sym == '-':
+		return 1
+	case tk == token.Strong && sym == '*':
+		return 1
+	case tk == token.KeyPhrase && sym == '*':
+		return 2
+	}
+	return 0
+}
+
+func repeatSym(tk token.Token, sym rune, n int) token.Lexeme {
+	return token.Lexeme{
+		Token: tk,
+		Val:   strings.Repeat(string(sym), n),
+	}
+}
+
+func runLength(lxs []token.Lexeme) int {
+	n := 0
+	for _, lx := range lxs {
+		n += utf8.RuneCountInString(lx.Val)
+	}
+	return n
+}
+
+func isFlankable(tk token.Token) bool {
+	switch tk {
+	case token.Positive, token.Negative, token.Strong, token.KeyPhrase:
+		return true
+	}
+	return false
+}
+
+// runEnd returns the index after the last lexeme in the run of flankable
+// symbols, made from the same character, that starts at 'start'.
+func runEnd(lxs []token.Lexeme, start int) int {
+	ru, _ := utf8.DecodeRuneInString(lxs[start].Val)
+	i := start + 1
+	for ; i < len(lxs) && isFlankable(lxs[i].Token); i++ {
+		if first, _ := utf8.DecodeRuneInString(lxs[i].Val); first != ru {
+			break
+		}
+	}
+	return i
+}
+
+// lastRuneOf returns the last rune of the lexeme at 'i' or a space if 'i' is
+// before the start of the line.
+func lastRuneOf(lxs []token.Lexeme, i int) rune {
+	if i < 0 || lxs[i].Val == "" {
+		return ' '
+	}
+	ru, _ := utf8.DecodeLastRuneInString(lxs[i].Val)
+	return ru
+}
+
+// firstRuneOf returns the first rune of the lexeme at 'i' or a space if 'i'
+// is after the end of the line.
+func firstRuneOf(lxs []token.Lexeme, i int) rune {
+	if i >= len(lxs) || lxs[i].Val == "" {
+		return ' '
+	}
+	ru, _ := utf8.DecodeRuneInString(lxs[i].Val)
+	return ru
+}
+
+func isLeftFlanking(prev, next rune) bool {
+	return !unicode.IsSpace(next) &&
+		(!isPunctRune(next) || unicode.IsSpace(prev) || isPunctRune(prev))
+}
+
+func isRightFlanking(prev, next rune) bool {
+	return !unicode.IsSpace(prev) &&
+		(!isPunctRune(prev) || unicode.IsSpace(next) || isPunctRune(next))
+}
+
+func isPunctRune(ru rune) bool {
+	return unicode.IsPunct(ru) || unicode.IsSymbol(ru)
+}
+
+func isWordRune(ru rune) bool {
+	return unicode.IsLetter(ru) || unicode.IsDigit(ru)
+}
@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/token"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestFlanking_1(t *testing.T) {
+
+	in := "A well-known fact, 1-2 weeks, C++, and x*y"
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Text, "A well-known fact, 1-2 weeks, C++, and x*y"),
+		},
+	}
+
+	act := ScanAllFlanking(in)
+	require.Equal(t, exp, act)
+}
+
+func TestFlanking_2(t *testing.T) {
+
+	in := "Cheese is +very tasty+ but -smelly-, *x*y*"
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Text, "Cheese is "),
+			lex(token.Positive, "+"),
+			lex(token.Text, "very tasty"),
+			lex(token.Positive, "+"),
+			lex(token.Text, " but "),
+			lex(token.Negative, "-"),
+			lex(token.Text, "smelly"),
+			lex(token.Negative, "-"),
+			lex(token.Text, ", "),
+			lex(token.Strong, "*"),
+			lex(token.Text, "x*y"),
+			lex(token.Strong, "*"),
+		},
+	}
+
+	act := ScanAllFlanking(in)
+	require.Equal(t, exp, act)
+}
+
+func TestFlanking_3(t *testing.T) {
+
+	in := ". -Unclosed negative"
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.BulPoint, "."),
+			lex(token.Text, " "),
+			lex(token.Negative, "-"),
+			lex(token.Text, "Unclosed negative"),
+		},
+	}
+
+	act := ScanAllFlanking(in)
+	require.Equal(t, exp, act)
+}
+
+func TestFlankingGolden_1(t *testing.T) {
+
+	in, e := ioutil.ReadFile(filepath.Join("testdata", "flanking.dw"))
+	require.Nil(t, e)
+
+	golden := filepath.Join("testdata", "flanking.golden")
+	act := goldenString(string(in))
+
+	if *update {
+		e = ioutil.WriteFile(golden, []byte(act), 0644)
+		require.Nil(t, e)
+	}
+
+	exp, e := ioutil.ReadFile(golden)
+	require.Nil(t, e)
+	require.Equal(t, string(exp), act)
+}
+
+// TestFlankingNoErrors_1 checks that any combination of symbols can be
+// scanned and parsed, with or without flanking rules, i.e. there is no such
+// thing as a syntax error.
+func TestFlankingNoErrors_1(t *testing.T) {
+
+	alphabet := []rune("ab1 .,!#+-*`\"$\\")
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		ru := make([]rune, rng.Intn(24))
+		for j := range ru {
+			ru[j] = alphabet[rng.Intn(len(alphabet))]
+		}
+
+		in := string(ru)
+		require.NotPanics(t, func() {
+			parser.ParseAll(ScanAll(in))
+			parser.ParseAll(ScanAllFlanking(in))
+		}, "Input: %q", in)
+	}
+}
+
+// goldenString scans each line of 's' with flanking rules and returns a
+// human readable listing of the lexemes.
+func goldenString(s string) string {
+	sb := strings.Builder{}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+
+	for i, lxs := range ScanAllFlanking(strings.Join(lines, "\n")) {
+		sb.WriteString(fmt.Sprintf("%q\n", lines[i]))
+		for _, lx := range lxs {
+			sb.WriteString(fmt.Sprintf("\t%-10s %q\n", lx.Token, lx.Val))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -1,51 +1,67 @@
 package scanner
 
 import (
+	"strconv"
 	"unicode"
 
 	"github.com/PaulioRandall/daft-wullie-go/token"
 )
 
-type lineScanner struct{ text []rune }
+type lineScanner struct {
+	text     []rune
+	prev     rune // Last rune sliced
+	flanking bool
+	links    *linkIndex // Built when the first labelled link is looked for
+	tail     int        // Runes to scan as text next, e.g. the '-' ending '#tag-'
+}
 
 func (ls *lineScanner) scanLine() []token.Lexeme {
 
 	ls.discardSpace()
 
 	switch {
-	case ls.matchStr("##"):
-		return []token.Lexeme{ls.slice(token.SubTopic, 2), ls.scanTextLine()}
-
-	case ls.matchStr("#"):
-		return []token.Lexeme{ls.slice(token.Topic, 1), ls.scanTextLine()}
+	case ls.match('#'):
+		lx := ls.sliceRun('#', token.Topic, token.SubTopic)
+		return []token.Lexeme{lx, ls.scanTextLine()}
 
-	case ls.matchStr(".."):
-		r := []token.Lexeme{ls.slice(token.SubBulPoint, 2)}
+	case ls.match('.'):
+		r := []token.Lexeme{ls.sliceRun('.', token.BulPoint, token.SubBulPoint)}
 		return append(r, ls.scanNodes()...)
 
-	case ls.matchStr("."):
-		r := []token.Lexeme{ls.slice(token.BulPoint, 1)}
-		return append(r, ls.scanNodes()...)
-
-	case ls.matchStr("!!"):
-		r := []token.Lexeme{ls.slice(token.SubNumPoint, 2)}
-		return append(r, ls.scanNodes()...)
-
-	case ls.matchStr("!"):
-		r := []token.Lexeme{ls.slice(token.NumPoint, 1)}
-		return append(r, ls.scanNodes()...)
+	case ls.match('!'):
+		lx := ls.sliceRun('!', token.NumPoint, token.SubNumPoint)
+		if n := ls.startLen(); n > 0 {
+			lx.Val += ls.slice(token.Undefined, n).Val // Explicit start
+		}
+		return append([]token.Lexeme{lx}, ls.scanNodes()...)
 
 	default:
 		return ls.scanNodes()
 	}
 }
 
+// startLen returns the number of digits at the start of the remaining text if
+// they form a valid explicit start number, i.e. one greater than zero that
+// fits in an int, else zero so the digits are scanned as text.
+func (ls *lineScanner) startLen() int {
+	i := 0
+	for ; ls.inRange(i) && digitMatcher(ls.at(i)); i++ {
+	}
+	if n, e := strconv.Atoi(string(ls.text[:i])); e != nil || n < 1 {
+		return 0
+	}
+	return i
+}
+
 func (ls *lineScanner) scanNodes() []token.Lexeme {
 	r := []token.Lexeme{}
 	for ls.inRange(0) {
 		lx := ls.scanNode()
 		r = append(r, lx)
 	}
+	if ls.flanking {
+		r = applyFlanking(r)
+	}
 	return normalise(r)
 }
 
@@ -65,6 +81,28 @@ func (ls *lineScanner) scanNode() token.Lexeme {
 		{"`", token.Snippet},
 	}
 
+	if ls.tail > 0 {
+		n := ls.tail
+		ls.tail = 0
+		return ls.slice(token.Text, n)
+	}
+
+	if n := ls.wikiLinkLen(0); n > 0 {
+		return ls.slice(token.WikiLink, n)
+	}
+
+	if n := ls.linkLen(0); n > 0 {
+		return ls.slice(token.Link, n)
+	}
+
+	if n := ls.tagLen(0); n > 0 {
+		lx := ls.slice(token.Tag, n)
+		for ls.inRange(ls.tail) && (ls.at(ls.tail) == '-' || ls.at(ls.tail) == '/') {
+			ls.tail++ // Trailing symbols are not part of the tag
+		}
+		return lx
+	}
+
 	for _, v := range keyTokens {
 		if ls.matchStr(v.sym) {
 			return ls.slice(v.tk, len(v.sym))
@@ -74,12 +112,25 @@ func (ls *lineScanner) scanNode() token.Lexeme {
 	return ls.scanText()
 }
 
+// wordNodeAt returns true if a link, wiki link, or tag starts at index 'i' of
+// the remaining text.
+func (ls *lineScanner) wordNodeAt(i int) bool {
+	return ls.wikiLinkLen(i) > 0 || ls.linkLen(i) > 0 || ls.tagLen(i) > 0
+}
+
 func (ls *lineScanner) scanTextLine() token.Lexeme {
 	return ls.sliceBy(token.Text, anyMatcher)
 }
 
+// scanText slices text up until the next symbol, link, or tag.
 func (ls *lineScanner) scanText() token.Lexeme {
-	return ls.sliceBy(token.Text, nonKeyMatcher)
+	i := 0
+	for ; ls.inRange(i) && nonKeyMatcher(ls.at(i)); i++ {
+		if i > 0 && ls.wordNodeAt(i) {
+			break
+		}
+	}
+	return ls.slice(token.Text, i)
 }
 
 func (ls *lineScanner) at(i int) rune {
@@ -118,12 +169,26 @@ func (ls *lineScanner) discardSpace() {
 func (ls *lineScanner) slice(tk token.Token, n int) token.Lexeme {
 	val := ls.text[:n]
 	ls.text = ls.text[n:]
+	if n > 0 {
+		ls.prev = val[n-1]
+	}
 	return token.Lexeme{
 		Token: tk,
 		Val:   string(val),
 	}
 }
 
+// sliceRun slices the run of 'ru' runes at the start of the remaining text.
+// The lexeme is of type 'top' if the run is a single rune else 'sub', the
+// length of the run, i.e. lexeme value, records the depth of nesting.
+func (ls *lineScanner) sliceRun(ru rune, top, sub token.Token) token.Lexeme {
+	lx := ls.sliceBy(top, func(v rune) bool { return v == ru })
+	if len(lx.Val) > 1 {
+		lx.Token = sub
+	}
+	return lx
+}
+
 func (ls *lineScanner) sliceBy(tk token.Token, f func(rune) bool) token.Lexeme {
 	i := 0
 	for ; ls.inRange(i) && f(ls.text[i]); i++ {
@@ -144,6 +209,10 @@ func anyMatcher(rune) bool {
 	return true
 }
 
+func digitMatcher(ru rune) bool {
+	return ru >= '0' && ru <= '9'
+}
+
 func spaceMatcher(ru rune) bool {
 	return unicode.IsSpace(ru)
 }
@@ -157,6 +226,9 @@ func normalise(lxs []token.Lexeme) []token.Lexeme {
 		return lxs
 	}
 	lxs = applyEscaping(lxs)
+	if len(lxs) == 0 {
+		return lxs // A lone trailing escape
+	}
 	return mergeLexemes(lxs)
 }
 
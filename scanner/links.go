@@ -0,0 +1,235 @@
+package scanner
+
+import (
+	"strings"
+	"unicode"
+)
+
+// urlSchemes are the URL prefixes that identify bare URLs within text.
+var urlSchemes = []string{
+	"https://",
+	"http://",
+	"ftp://",
+	"mailto:",
+}
+
+// linkLen returns the length of the link starting at index 'i' of the
+// remaining text or zero if there isn't one. Links come in two forms:
+// - bare URLs, e.g. 'https://example.com', that are not part of a word
+// - explicit links with a label, e.g. '[Example](https://example.com)'
+func (ls *lineScanner) linkLen(i int) int {
+	if ls.wikiLinkLen(i) > 0 {
+		return 0
+	}
+	if n := ls.labelledLinkLen(i); n > 0 {
+		return n
+	}
+	return ls.bareURLLen(i)
+}
+
+// wikiLinkLen returns the length of the '[[Note]]' or '[[Note#Topic]]' wiki
+// link starting at index 'i' of the remaining text or zero if there isn't
+// one. The target may not be blank or contain '[' or ']'.
+func (ls *lineScanner) wikiLinkLen(i int) int {
+	if !ls.matchStrAt(i, "[[") {
+		return 0
+	}
+
+	j := i + 2
+	for ; ls.inRange(j) && ls.at(j) != '[' && ls.at(j) != ']'; j++ {
+	}
+
+	if !ls.matchStrAt(j, "]]") || strings.TrimSpace(string(ls.text[i+2:j])) == "" {
+		return 0
+	}
+	return j + 2 - i
+}
+
+// labelledLinkLen returns the length of the '[label](url)' link starting at
+// index 'i' of the remaining text or zero if there isn't one. The label may
+// not contain ']' and the URL may not be empty, contain whitespace, or contain
+// unbalanced parentheses.
+func (ls *lineScanner) labelledLinkLen(i int) int {
+	if !ls.inRange(i) || ls.at(i) != '[' {
+		return 0
+	}
+
+	if ls.links == nil {
+		ls.links = newLinkIndex(ls.text)
+	}
+	idx, off := ls.links, ls.links.size-len(ls.text)
+
+	j := idx.bracket[off+i+1] - off
+	if !ls.inRange(j+1) || ls.at(j+1) != '(' {
+		return 0
+	}
+
+	k := idx.paren[off+j+2] - off
+	if !ls.inRange(k) || k == j+2 || idx.space[off+j+2]-off < k {
+		return 0
+	}
+	return k + 1 - i
+}
+
+// linkIndex records, for each index of a line, where the next ']', the next
+// whitespace, and the ')' closing the parentheses from that index, if any,
+// are. It is built in a single pass over the line so finding the end of each
+// labelled link does not rescan the rest of the line. Indexes are those of
+// the line when the index was built, i.e. when it had 'size' runes remaining,
+// and the length of the line means not found.
+type linkIndex struct {
+	size    int
+	bracket []int
+	space   []int
+	paren   []int
+}
+
+func newLinkIndex(text []rune) *linkIndex {
+	n := len(text)
+	idx := &linkIndex{
+		size:    n,
+		bracket: make([]int, n+1),
+		space:   make([]int, n+1),
+		paren:   make([]int, n+1),
+	}
+
+	idx.bracket[n], idx.space[n] = n, n
+	for i := n - 1; i >= 0; i-- {
+		idx.bracket[i], idx.space[i] = idx.bracket[i+1], idx.space[i+1]
+		if text[i] == ']' {
+			idx.bracket[i] = i
+		}
+		if unicode.IsSpace(text[i]) {
+			idx.space[i] = i
+		}
+	}
+
+	// Each index waits for the first ')' that takes the depth of parentheses
+	// below the depth it started at. The depth of those waiting never
+	// decreases towards the top of the stack so they're resolved from the top.
+	type start struct{ i, depth int }
+	waiting := []start{}
+	depth := 0
+
+	for i, ru := range text {
+		waiting = append(waiting, start{i, depth})
+		switch ru {
+		case '(':
+			depth++
+		case ')':
+			for len(waiting) > 0 && waiting[len(waiting)-1].depth == depth {
+				idx.paren[waiting[len(waiting)-1].i] = i
+				waiting = waiting[:len(waiting)-1]
+			}
+			depth--
+		}
+	}
+	idx.paren[n] = n
+	for _, w := range waiting {
+		idx.paren[w.i] = n
+	}
+
+	return idx
+}
+
+// bareURLLen returns the length of the bare URL starting at index 'i' of the
+// remaining text or zero if there isn't one. A URL ends at the first
+// whitespace but trailing punctuation, e.g. the full stop ending a sentence,
+// and unbalanced closing parentheses are not considered part of it.
+func (ls *lineScanner) bareURLLen(i int) int {
+	if isWordRune(ls.runeBefore(i)) || !ls.matchSchemeAt(i) {
+		return 0
+	}
+
+	j := i
+	for ; ls.inRange(j) && !unicode.IsSpace(ls.at(j)); j++ {
+	}
+
+	for j > i {
+		ru := ls.at(j - 1)
+		if strings.ContainsRune(`.,;:!?'"`, ru) {
+			j--
+		} else if ru == ')' && !ls.balanced(i, j) {
+			j--
+		} else {
+			break
+		}
+	}
+
+	url := string(ls.text[i:j])
+	for _, scheme := range urlSchemes {
+		if url == scheme {
+			return 0 // Scheme without an address
+		}
+	}
+	return j - i
+}
+
+func (ls *lineScanner) matchStrAt(i int, s string) bool {
+	for _, ru := range s {
+		if !ls.inRange(i) || ls.at(i) != ru {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+func (ls *lineScanner) matchSchemeAt(i int) bool {
+	for _, scheme := range urlSchemes {
+		if ls.matchStrAt(i, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// balanced returns true if the parentheses within text[i:j] are balanced.
+func (ls *lineScanner) balanced(i, j int) bool {
+	n := 0
+	for _, ru := range ls.text[i:j] {
+		switch ru {
+		case '(':
+			n++
+		case ')':
+			n--
+		}
+	}
+	return n >= 0
+}
+
+// tagLen returns the length of the tag, e.g. '#idea', starting at index 'i'
+// of the remaining text or zero if there isn't one. A tag must not be part
+// of a word and its name is made from letters, digits, '_', '-', and '/' but
+// must start with a letter, digit, or '_' and cannot end with '-' or '/'.
+func (ls *lineScanner) tagLen(i int) int {
+	if !ls.inRange(i+1) || ls.at(i) != '#' || isWordRune(ls.runeBefore(i)) {
+		return 0
+	}
+
+	if ru := ls.at(i + 1); !isWordRune(ru) && ru != '_' {
+		return 0
+	}
+
+	j := i + 1
+	for ; ls.inRange(j) && isTagRune(ls.at(j)); j++ {
+	}
+
+	for ls.at(j-1) == '-' || ls.at(j-1) == '/' {
+		j--
+	}
+	return j - i
+}
+
+func isTagRune(ru rune) bool {
+	return isWordRune(ru) || ru == '_' || ru == '-' || ru == '/'
+}
+
+// runeBefore returns the rune before index 'i' of the remaining text,
+// including text already scanned, or a space if 'i' is the start of the line.
+func (ls *lineScanner) runeBefore(i int) rune {
+	if i > 0 {
+		return ls.at(i - 1)
+	}
+	return ls.prev
+}
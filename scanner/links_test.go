@@ -0,0 +1,154 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/token"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLink_1(t *testing.T) {
+
+	in := "Source: https://en.wikipedia.org/wiki/Cheese $2021"
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Text, "Source: "),
+			lex(token.Link, "https://en.wikipedia.org/wiki/Cheese"),
+			lex(token.Text, " "),
+			lex(token.Artifact, "$"),
+			lex(token.Text, "2021"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestLink_2(t *testing.T) {
+
+	in := "(see http://a.org/x_(y)), then mailto:me@a.org."
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Text, "(see "),
+			lex(token.Link, "http://a.org/x_(y)"),
+			lex(token.Text, "), then "),
+			lex(token.Link, "mailto:me@a.org"),
+			lex(token.Text, "."),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestLink_3(t *testing.T) {
+
+	in := "+[Cheese](https://a.org/Cheese_(food))+ [not](a link) xhttp://no"
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Positive, "+"),
+			lex(token.Link, "[Cheese](https://a.org/Cheese_(food))"),
+			lex(token.Positive, "+"),
+			lex(token.Text, " [not](a link) xhttp://no"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestLink_4(t *testing.T) {
+
+	in := `\https://a.org and https:// alone`
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Text, "https://a.org and https:// alone"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestLink_5(t *testing.T) {
+
+	// Links are recognised however long they are
+	url := "https://a.org/" + strings.Repeat("x", 100000) + "_(y)"
+	in := "[long](" + url + ")"
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Link, in),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestLink_6(t *testing.T) {
+
+	// Long lines of brackets, or failed links, must not take quadratic time
+	for _, in := range []string{
+		strings.Repeat("[", 40000),
+		strings.Repeat("[](x", 10000),
+		strings.Repeat("[a](b(", 10000),
+		strings.Repeat("[a](b", 10000) + " " + strings.Repeat(")", 10000),
+		strings.Repeat("cheese ", 6000),
+	} {
+		exp := [][]token.Lexeme{
+			[]token.Lexeme{
+				lex(token.Text, in),
+			},
+		}
+		require.Equal(t, exp, ScanAll(in))
+	}
+}
+
+func TestTag_1(t *testing.T) {
+
+	in := "#topic"
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Topic, "#"),
+			lex(token.Text, "topic"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestTag_2(t *testing.T) {
+
+	in := ". C# is not a tag but #follow-up/cheese- and (#_x) are, # isn't"
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.BulPoint, "."),
+			lex(token.Text, " C# is not a tag but "),
+			lex(token.Tag, "#follow-up/cheese"),
+			lex(token.Text, "- and ("),
+			lex(token.Tag, "#_x"),
+			lex(token.Text, ") are, # isn't"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestWikiLink_1(t *testing.T) {
+
+	in := "See [[Other Note#Topic]], [[ ]] and [[a[b]]"
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.Text, "See "),
+			lex(token.WikiLink, "[[Other Note#Topic]]"),
+			lex(token.Text, ", [[ ]] and [[a[b]]"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
@@ -13,18 +13,41 @@ type ScanLine func() ([]token.Lexeme, ScanLine)
 
 // NewScanner creates an initial ScanLine function for the text 's'.
 func NewScanner(s string) ScanLine {
-	ss := &scriptScanner{lines: splitLines(s)}
+	return newScanner(s, false)
+}
+
+// NewFlankingScanner creates an initial ScanLine function for the text 's'
+// that applies flanking rules to the '+', '-', and '*' symbols. See
+// applyFlanking for details.
+func NewFlankingScanner(s string) ScanLine {
+	return newScanner(s, true)
+}
+
+// ScanAll scans all lines in 's' into a slice of lexeme slices, each
+// representing a line of annotated text.
+func ScanAll(s string) [][]token.Lexeme {
+	return scanAll(NewScanner(s))
+}
+
+// ScanAllFlanking is the same as ScanAll but applies flanking rules to the
+// '+', '-', and '*' symbols. See applyFlanking for details.
+func ScanAllFlanking(s string) [][]token.Lexeme {
+	return scanAll(NewFlankingScanner(s))
+}
+
+func newScanner(s string, flanking bool) ScanLine {
+	ss := &scriptScanner{
+		lines:    splitLines(s),
+		flanking: flanking,
+	}
 	if !ss.more() {
 		return nil
 	}
 	return scanner(ss)
 }
 
-// ScanAll scans all lines in 's' into a slice of lexeme slices, each
-// representing a line of annotated text.
-func ScanAll(s string) [][]token.Lexeme {
+func scanAll(f ScanLine) [][]token.Lexeme {
 	var (
-		f   = NewScanner(s)
 		r   = [][]token.Lexeme{}
 		lxs []token.Lexeme
 	)
@@ -51,8 +74,9 @@ func splitLines(s string) []string {
 }
 
 type scriptScanner struct {
-	idx   int
-	lines []string
+	idx      int
+	lines    []string
+	flanking bool
 }
 
 func (ss *scriptScanner) more() bool {
@@ -61,7 +85,9 @@ func (ss *scriptScanner) more() bool {
 
 func (ss *scriptScanner) scanLine() []token.Lexeme {
 	ls := &lineScanner{
-		text: []rune(ss.lines[ss.idx]),
+		text:     []rune(ss.lines[ss.idx]),
+		prev:     ' ',
+		flanking: ss.flanking,
 	}
 	ss.idx++
 	return ls.scanLine()
@@ -79,6 +79,20 @@ func TestSubTopic_1(t *testing.T) {
 	require.Equal(t, exp, act)
 }
 
+func TestSubTopic_2(t *testing.T) {
+
+	in := `#### Sub sub sub topic`
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.SubTopic, "####"),
+			lex(token.Text, " Sub sub sub topic"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
 func TestBulletPoint_1(t *testing.T) {
 
 	in := `. Point`
@@ -107,6 +121,20 @@ func TestSubBulletPoint_1(t *testing.T) {
 	require.Equal(t, exp, act)
 }
 
+func TestSubBulletPoint_2(t *testing.T) {
+
+	in := `... Point`
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.SubBulPoint, "..."),
+			lex(token.Text, " Point"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
 func TestNumberPoint_1(t *testing.T) {
 
 	in := `! Point`
@@ -135,6 +163,62 @@ func TestNumberPoint_2(t *testing.T) {
 	require.Equal(t, exp, act)
 }
 
+func TestNumberPoint_3(t *testing.T) {
+
+	in := `!!! Point!`
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.SubNumPoint, "!!!"),
+			lex(token.Text, " Point!"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestNumberPoint_4(t *testing.T) {
+
+	in := `!!12 Point`
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.SubNumPoint, "!!12"),
+			lex(token.Text, " Point"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestNumberPoint_5(t *testing.T) {
+
+	in := `!0 zero items`
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.NumPoint, "!"),
+			lex(token.Text, "0 zero items"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
+func TestNumberPoint_6(t *testing.T) {
+
+	in := `!99999999999999999999 big`
+	exp := [][]token.Lexeme{
+		[]token.Lexeme{
+			lex(token.NumPoint, "!"),
+			lex(token.Text, "99999999999999999999 big"),
+		},
+	}
+
+	act := ScanAll(in)
+	require.Equal(t, exp, act)
+}
+
 func TestNodes_1(t *testing.T) {
 
 	in := "**+-*\"$`"
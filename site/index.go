@@ -0,0 +1,165 @@
+package site
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/tags"
+)
+
+type (
+	// entry is a key phrase, tag, or artifact along with where it appears.
+	entry struct {
+		text    string
+		sources []source
+	}
+
+	source struct {
+		path   string
+		title  string // Title of the note
+		topic  string // Title of the enclosing topic, if any
+		anchor string // Anchor of the enclosing topic, see ast.Anchors
+		line   int
+	}
+
+	index struct {
+		keyPhrases []entry
+		tags       []entry
+		artifacts  []entry
+	}
+
+	// document is a note within the search index.
+	document struct {
+		Page       string   `json:"page"`
+		Title      string   `json:"title"`
+		Date       string   `json:"date,omitempty"`
+		Topics     []string `json:"topics"`
+		Tags       []string `json:"tags"`
+		KeyPhrases []string `json:"keyPhrases"`
+		Text       string   `json:"text"`
+	}
+)
+
+// collect indexes the key phrases, tags, and artifacts of 'notes'. Entries
+// are matched regardless of case and sorted.
+func collect(notes []*notebook.Note) index {
+	kp, tg, af := entries{}, entries{}, entries{}
+
+	for _, n := range notes {
+		topic, anchor := "", ""
+		anchors := ast.Anchors{}
+		for i, line := range n.Notes {
+			if line.Type() == ast.Topic || line.Type() == ast.SubTopic {
+				topic = strings.TrimSpace(line.Text())
+				anchor = anchors.Next(topic)
+			}
+			src := source{path: n.Path, title: n.Meta.Title, topic: topic, anchor: anchor, line: i + 1}
+
+			walk(line, func(node ast.Node) {
+				switch node.Type() {
+				case ast.KeyPhrase:
+					kp.add(strings.TrimSpace(node.Text()), src)
+				case ast.Tag:
+					tg.add("#"+tags.Normalise(node.Text()), src)
+				case ast.Artifact:
+					af.add(strings.TrimSpace(node.Text()), src)
+				}
+			})
+		}
+	}
+
+	return index{
+		keyPhrases: kp.sorted(),
+		tags:       tg.sorted(),
+		artifacts:  af.sorted(),
+	}
+}
+
+type entries map[string]*entry
+
+func (es entries) add(text string, src source) {
+	if text == "" {
+		return
+	}
+	k := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	e, ok := es[k]
+	if !ok {
+		e = &entry{text: text}
+		es[k] = e
+	}
+	e.sources = append(e.sources, src)
+}
+
+func (es entries) sorted() []entry {
+	r := make([]entry, 0, len(es))
+	for _, e := range es {
+		r = append(r, *e)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return strings.ToLower(r[i].text) < strings.ToLower(r[j].text)
+	})
+	return r
+}
+
+// walk calls 'f' for 'n' and its descendants but not for the descendants of
+// artifacts and snippets, whose phrases belong to them.
+func walk(n ast.Node, f func(ast.Node)) {
+	f(n)
+	if n.Type() == ast.Artifact || n.Type() == ast.Snippet {
+		return
+	}
+	if p, ok := n.(ast.Parent); ok {
+		for _, c := range p.Nodes() {
+			walk(c, f)
+		}
+	}
+}
+
+// searchIndex returns the JSON search index of 'notes'.
+func searchIndex(notes []*notebook.Note) ([]byte, error) {
+	docs := make([]document, len(notes))
+
+	for i, n := range notes {
+		d := document{
+			Page:       rel("index.html", PagePath(n.Path)),
+			Title:      n.Meta.Title,
+			Topics:     []string{},
+			Tags:       []string{},
+			KeyPhrases: []string{},
+		}
+		if !n.Meta.Date.IsZero() {
+			d.Date = n.Meta.Date.Format("2006-01-02")
+		}
+
+		text := []string{}
+		seen := map[string]bool{}
+		for _, line := range n.Notes {
+			if line.Type() == ast.Topic || line.Type() == ast.SubTopic {
+				d.Topics = append(d.Topics, strings.TrimSpace(line.Text()))
+			}
+			if s := strings.TrimSpace(line.Text()); s != "" {
+				text = append(text, s)
+			}
+
+			walk(line, func(node ast.Node) {
+				switch node.Type() {
+				case ast.KeyPhrase:
+					d.KeyPhrases = append(d.KeyPhrases, strings.TrimSpace(node.Text()))
+				case ast.Tag:
+					if t := tags.Normalise(node.Text()); !seen[t] {
+						seen[t] = true
+						d.Tags = append(d.Tags, t)
+					}
+				}
+			})
+		}
+
+		d.Text = strings.Join(text, " ")
+		docs[i] = d
+	}
+
+	return json.Marshal(docs)
+}
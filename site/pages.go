@@ -0,0 +1,198 @@
+package site
+
+import (
+	gohtml "html"
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/wiki"
+)
+
+// style is added to html.Style for the site's own elements.
+const style = `nav.dw-site { margin-bottom: 1em; }
+nav.dw-toc { border-left: 3px solid #d0d7de; padding-left: 1em; }
+nav.dw-toc ul { list-style: none; padding-left: 0; }
+.dw-toc-2 { margin-left: 1em; }
+.dw-toc-3 { margin-left: 2em; }
+.dw-toc-4, .dw-toc-5, .dw-toc-6 { margin-left: 3em; }
+.dw-meta { color: #57606a; }
+`
+
+// layout wraps 'body' in the page at 'page' with links to the site's other
+// pages.
+func layout(page, title, body string) string {
+	sb := &strings.Builder{}
+	sb.WriteString(`<nav class="dw-site">`)
+	for i, l := range []struct{ href, text string }{
+		{"index.html", "Index"},
+		{"key-phrases.html", "Key Phrases"},
+		{"tags.html", "Tags"},
+		{"artifacts.html", "Artifacts"},
+	} {
+		if i > 0 {
+			sb.WriteString(" · ")
+		}
+		sb.WriteString(`<a href="` + rel(page, l.href) + `">` + l.text + "</a>")
+	}
+	sb.WriteString("</nav>\n")
+	sb.WriteString(body)
+
+	doc := html.Document(title, sb.String())
+	return strings.Replace(doc, "</style>", style+"</style>", 1)
+}
+
+func notePage(n *notebook.Note, res *wiki.Resolver) string {
+	page := PagePath(n.Path)
+	sb := &strings.Builder{}
+
+	if !n.Meta.Date.IsZero() {
+		sb.WriteString(`<p class="dw-meta">` + n.Meta.Date.Format("2006-01-02") + "</p>\n")
+	}
+	sb.WriteString(toc(n.Notes))
+	sb.WriteString(html.FragmentWith(n.Notes, html.Options{
+		WikiHref: func(l ast.WikiLinkNode) (string, bool) {
+			link := res.Resolve(n.Path, l)
+			if link.Broken() {
+				return "", false
+			}
+			href := rel(page, PagePath(link.To))
+			if link.To == n.Path {
+				href = ""
+			}
+			if link.Anchor != "" {
+				href += "#" + link.Anchor
+			}
+			return href, true
+		},
+	}))
+
+	return layout(page, n.Meta.Title, sb.String())
+}
+
+// toc returns the table of contents of 'notes', empty if there are fewer
+// than two topics.
+func toc(notes ast.Notes) string {
+	sb := &strings.Builder{}
+	anchors := ast.Anchors{}
+	count := 0
+
+	for _, n := range notes {
+		if n.Type() != ast.Topic && n.Type() != ast.SubTopic {
+			continue
+		}
+		title := strings.TrimSpace(n.Text())
+		level := ast.Level(n)
+		if level > 6 {
+			level = 6
+		}
+
+		sb.WriteString(`<li class="dw-toc-` + strconv.Itoa(level) + `">`)
+		sb.WriteString(`<a href="#` + anchors.Next(title) + `">` + gohtml.EscapeString(title) + "</a></li>\n")
+		count++
+	}
+
+	if count < 2 {
+		return ""
+	}
+	return `<nav class="dw-toc">` + "\n<ul>\n" + sb.String() + "</ul>\n</nav>\n"
+}
+
+func indexPage(notes []*notebook.Note) string {
+	sb := &strings.Builder{}
+	sb.WriteString("<h1>Notes</h1>\n")
+	sb.WriteString(`<input id="dw-search" type="search" placeholder="Search">` + "\n")
+	sb.WriteString(`<ul id="dw-results"></ul>` + "\n")
+
+	sb.WriteString(`<ul id="dw-notes">` + "\n")
+	for _, n := range notes {
+		sb.WriteString(`<li><a href="` + gohtml.EscapeString(rel("index.html", PagePath(n.Path))) + `">`)
+		sb.WriteString(gohtml.EscapeString(n.Meta.Title) + "</a>")
+		sb.WriteString(` <span class="dw-meta">` + gohtml.EscapeString(n.Path))
+		if !n.Meta.Date.IsZero() {
+			sb.WriteString(", " + n.Meta.Date.Format("2006-01-02"))
+		}
+		sb.WriteString(", " + strconv.Itoa(n.Meta.Words) + " words</span></li>\n")
+	}
+	sb.WriteString("</ul>\n")
+
+	sb.WriteString(`<script src="search.js"></script>` + "\n")
+	sb.WriteString(searchScript)
+	return layout("index.html", "Notes", sb.String())
+}
+
+// searchScript searches the index for notes containing every word typed,
+// ranking matches within titles and topics first.
+const searchScript = `<script>
+(function() {
+  var input = document.getElementById("dw-search");
+  var results = document.getElementById("dw-results");
+  var notes = document.getElementById("dw-notes");
+
+  function score(doc, words) {
+    var total = 0;
+    for (var i = 0; i < words.length; i++) {
+      var w = words[i], s = 0;
+      if (doc.title.toLowerCase().indexOf(w) >= 0) s += 10;
+      if (doc.topics.join(" ").toLowerCase().indexOf(w) >= 0) s += 5;
+      if (doc.tags.join(" ").indexOf(w) >= 0) s += 5;
+      if (doc.keyPhrases.join(" ").toLowerCase().indexOf(w) >= 0) s += 3;
+      if (doc.text.toLowerCase().indexOf(w) >= 0) s += 1;
+      if (s === 0) return 0;
+      total += s;
+    }
+    return total;
+  }
+
+  input.addEventListener("input", function() {
+    var words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.innerHTML = "";
+    notes.style.display = words.length ? "none" : "";
+    if (!words.length) return;
+
+    dwSearchIndex.map(function(doc) {
+      return {doc: doc, score: score(doc, words)};
+    }).filter(function(m) {
+      return m.score > 0;
+    }).sort(function(a, b) {
+      return b.score - a.score;
+    }).forEach(function(m) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = m.doc.page;
+      a.textContent = m.doc.title;
+      li.appendChild(a);
+      results.appendChild(li);
+    });
+  });
+})();
+</script>
+`
+
+func entriesPage(title string, entries []entry) string {
+	page := ast.Slug(title) + ".html"
+	sb := &strings.Builder{}
+	sb.WriteString("<h1>" + title + "</h1>\n")
+
+	if len(entries) == 0 {
+		sb.WriteString("<p>None yet.</p>\n")
+	}
+
+	for _, e := range entries {
+		sb.WriteString(`<h2 id="` + ast.Slug(e.text) + `">` + gohtml.EscapeString(e.text) + "</h2>\n<ul>\n")
+		for _, s := range e.sources {
+			href := rel(page, PagePath(s.path))
+			text := s.title
+			if s.topic != "" {
+				href += "#" + s.anchor
+				text += " › " + s.topic
+			}
+			sb.WriteString(`<li><a href="` + gohtml.EscapeString(href) + `">` + gohtml.EscapeString(text) + "</a>")
+			sb.WriteString(` <span class="dw-meta">line ` + strconv.Itoa(s.line) + "</span></li>\n")
+		}
+		sb.WriteString("</ul>\n")
+	}
+	return layout(page, title, sb.String())
+}
@@ -0,0 +1,219 @@
+// Package site renders a notebook as a static HTML site. The site has:
+//   - a page per note, with a table of contents, at the path of the note
+//     with a '.html' extension, e.g. 'cheese/curds.html'
+//   - an index page listing every note with a search box
+//   - pages listing every key phrase, tag, and artifact linking back to the
+//     topics they appear in
+//   - a search index, 'search.json', along with 'search.js' holding the same
+//     index for browsing the site from disk where 'search.json' can't be
+//     fetched
+//
+// Builds are incremental. A manifest of the notes built is kept within the
+// site so a note's page is only rendered again if the note changed, or if
+// the titles or topics of the notes changed since wiki links may resolve
+// differently. Pages for removed notes are deleted and other pages are only
+// written if their content changed.
+package site
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/wiki"
+)
+
+// ManifestFile is the name of the manifest kept within the site.
+const ManifestFile = ".dw-site.json"
+
+// manifestVersion is increased whenever the pages rendered for notes change
+// so sites built by older versions are fully rebuilt.
+const manifestVersion = 1
+
+type (
+	// Report lists the pages, relative to the site root, that a build wrote
+	// and removed, along with the number of note pages that were unchanged.
+	Report struct {
+		Written   []string
+		Removed   []string
+		Unchanged int
+	}
+
+	manifest struct {
+		Version int              `json:"version"`
+		Links   string           `json:"links"` // Hash of the titles and topics of all notes
+		Notes   map[string]built `json:"notes"`
+	}
+
+	// built is a note as it was when its page was last rendered.
+	built struct {
+		ModTime time.Time `json:"modTime"`
+		Size    int64     `json:"size"`
+		Page    string    `json:"page"`
+	}
+)
+
+// Build renders the notebook 'nb' into the directory 'out'.
+func Build(nb *notebook.Notebook, out string) (Report, error) {
+	r := Report{Written: []string{}, Removed: []string{}}
+
+	notes, e := nb.Notes()
+	if e != nil {
+		return r, e
+	}
+
+	old := readManifest(out)
+	m := manifest{
+		Version: manifestVersion,
+		Links:   linkHash(notes),
+		Notes:   map[string]built{},
+	}
+	fresh := old.Version != m.Version || old.Links != m.Links
+
+	res := resolver(notes)
+	for _, n := range notes {
+		b := built{ModTime: n.ModTime, Size: n.Size, Page: PagePath(n.Path)}
+		m.Notes[n.Path] = b
+
+		prev, ok := old.Notes[n.Path]
+		if !fresh && ok && prev.same(b) && exists(filepath.Join(out, b.Page)) {
+			r.Unchanged++
+			continue
+		}
+
+		if e := write(out, b.Page, notePage(n, res), &r); e != nil {
+			return r, e
+		}
+	}
+
+	for p, b := range old.Notes {
+		if _, ok := m.Notes[p]; ok {
+			continue
+		}
+		e := os.Remove(filepath.Join(out, filepath.FromSlash(b.Page)))
+		if e != nil && !os.IsNotExist(e) {
+			return r, e
+		}
+		r.Removed = append(r.Removed, b.Page)
+	}
+	sort.Strings(r.Removed)
+
+	idx := collect(notes)
+	search, e := searchIndex(notes)
+	if e != nil {
+		return r, e
+	}
+
+	pages := []struct{ path, content string }{
+		{"index.html", indexPage(notes)},
+		{"key-phrases.html", entriesPage("Key Phrases", idx.keyPhrases)},
+		{"tags.html", entriesPage("Tags", idx.tags)},
+		{"artifacts.html", entriesPage("Artifacts", idx.artifacts)},
+		{"search.json", string(search)},
+		{"search.js", "var dwSearchIndex = " + string(search) + ";\n"},
+	}
+	for _, p := range pages {
+		if e := write(out, p.path, p.content, &r); e != nil {
+			return r, e
+		}
+	}
+
+	return r, writeManifest(out, m)
+}
+
+func (b built) same(o built) bool {
+	return b.ModTime.Equal(o.ModTime) && b.Size == o.Size && b.Page == o.Page
+}
+
+// PagePath returns the path of the page of the note at 'notePath', both
+// relative to their roots.
+func PagePath(notePath string) string {
+	return strings.TrimSuffix(notePath, path.Ext(notePath)) + ".html"
+}
+
+// rel returns the relative URL from the page 'from' to the page 'to', both
+// relative to the site root. The path of 'to' is escaped so note names such
+// as 'c#.dw' link to their page rather than a fragment.
+func rel(from, to string) string {
+	p := (&url.URL{Path: to}).EscapedPath()
+	if i := strings.IndexAny(p, ":/"); i >= 0 && p[i] == ':' {
+		p = "./" + p // Not a scheme
+	}
+	return strings.Repeat("../", strings.Count(from, "/")) + p
+}
+
+// write writes 'content' to the page 'page' within 'out' if it differs from
+// what is there.
+func write(out, page, content string, r *Report) error {
+	file := filepath.Join(out, filepath.FromSlash(page))
+	if b, e := ioutil.ReadFile(file); e == nil && string(b) == content {
+		return nil
+	}
+
+	if e := os.MkdirAll(filepath.Dir(file), 0755); e != nil {
+		return e
+	}
+	if e := ioutil.WriteFile(file, []byte(content), 0644); e != nil {
+		return e
+	}
+	r.Written = append(r.Written, page)
+	return nil
+}
+
+func exists(file string) bool {
+	_, e := os.Stat(file)
+	return e == nil
+}
+
+func readManifest(out string) manifest {
+	m := manifest{}
+	if b, e := ioutil.ReadFile(filepath.Join(out, ManifestFile)); e == nil {
+		json.Unmarshal(b, &m) // A bad manifest means a full rebuild
+	}
+	if m.Notes == nil {
+		m.Notes = map[string]built{}
+	}
+	return m
+}
+
+func writeManifest(out string, m manifest) error {
+	b, e := json.MarshalIndent(m, "", "  ")
+	if e != nil {
+		return e
+	}
+	return ioutil.WriteFile(filepath.Join(out, ManifestFile), b, 0644)
+}
+
+// linkHash returns a hash of what wiki links resolve to, i.e. the paths,
+// titles, and topics of 'notes'.
+func linkHash(notes []*notebook.Note) string {
+	h := sha256.New()
+	for _, n := range notes {
+		h.Write([]byte(n.Path + "\n" + wiki.Title(n.Notes) + "\n"))
+		for _, line := range n.Notes {
+			if line.Type() == ast.Topic || line.Type() == ast.SubTopic {
+				h.Write([]byte(ast.Slug(line.Text()) + "\n"))
+			}
+		}
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func resolver(notes []*notebook.Note) *wiki.Resolver {
+	wn := make([]wiki.Note, len(notes))
+	for i, n := range notes {
+		wn[i] = wiki.Note{Path: n.Path, Notes: n.Notes}
+	}
+	return wiki.NewResolver(wn)
+}
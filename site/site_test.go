@@ -0,0 +1,121 @@
+package site
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+func writeFile(t *testing.T, root, path, s string) {
+	file := filepath.Join(root, filepath.FromSlash(path))
+	require.Nil(t, os.MkdirAll(filepath.Dir(file), 0755))
+	require.Nil(t, ioutil.WriteFile(file, []byte(s), 0644))
+}
+
+func read(t *testing.T, root, path string) string {
+	b, e := ioutil.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+	require.Nil(t, e)
+	return string(b)
+}
+
+func tempSite(t *testing.T) (*notebook.Notebook, string, func()) {
+	dir, e := ioutil.TempDir("", "site")
+	require.Nil(t, e)
+
+	src := filepath.Join(dir, "notes")
+	writeFile(t, src, "cheese.dw", "# Cheese\n$2021-02-06$\n## Milk\n. **Rennet** #dairy\n## Curds\nSee [[Curds#Cutting]]")
+	writeFile(t, src, "making/curds.dw", "# Curds\n## Cutting\n. **rennet** sets #Dairy")
+
+	nb, e := notebook.Open(src)
+	require.Nil(t, e)
+	return nb, filepath.Join(dir, "site"), func() { os.RemoveAll(dir) }
+}
+
+func TestBuild_1(t *testing.T) {
+	nb, out, cleanup := tempSite(t)
+	defer cleanup()
+
+	r, e := Build(nb, out)
+	require.Nil(t, e)
+	require.Equal(t, []string{
+		"cheese.html", "making/curds.html", "index.html", "key-phrases.html",
+		"tags.html", "artifacts.html", "search.json", "search.js",
+	}, r.Written)
+
+	page := read(t, out, "cheese.html")
+	require.Contains(t, page, `<li class="dw-toc-2"><a href="#milk">Milk</a></li>`)
+	require.Contains(t, page, `<a class="dw-wiki-link" href="making/curds.html#cutting">`)
+	require.Contains(t, read(t, out, "making/curds.html"), `<a href="../index.html">Index</a>`)
+
+	kp := read(t, out, "key-phrases.html")
+	require.Contains(t, kp, `<h2 id="rennet">Rennet</h2>`)
+	require.Contains(t, kp, `<a href="making/curds.html#cutting">Curds › Cutting</a>`)
+	require.Contains(t, read(t, out, "tags.html"), `<h2 id="dairy">#dairy</h2>`)
+	require.Contains(t, read(t, out, "artifacts.html"), `<h2 id="2021-02-06">2021-02-06</h2>`)
+
+	search := read(t, out, "search.json")
+	require.Contains(t, search, `{"page":"cheese.html","title":"Cheese","date":"2021-02-06","topics":["Cheese","Milk","Curds"],"tags":["dairy"],"keyPhrases":["Rennet"]`)
+}
+
+func TestBuild_2(t *testing.T) {
+	nb, out, cleanup := tempSite(t)
+	defer cleanup()
+
+	_, e := Build(nb, out)
+	require.Nil(t, e)
+
+	r, e := Build(nb, out)
+	require.Nil(t, e)
+	require.Empty(t, r.Written)
+	require.Equal(t, 2, r.Unchanged)
+
+	// Changing a note's text only rebuilds its page and affected listings
+	time.Sleep(10 * time.Millisecond)
+	writeFile(t, nb.Root(), "cheese.dw", "# Cheese\n$2021-02-07$\n## Milk\n. **Rennet** #dairy\n## Curds\nSee [[Curds#Cutting]]")
+	r, e = Build(nb, out)
+	require.Nil(t, e)
+	require.Equal(t, []string{"cheese.html", "index.html", "artifacts.html", "search.json", "search.js"}, r.Written)
+
+	// Removing a note changes the links so every page is rebuilt
+	require.Nil(t, os.Remove(filepath.Join(nb.Root(), "making", "curds.dw")))
+	r, e = Build(nb, out)
+	require.Nil(t, e)
+	require.Equal(t, []string{"making/curds.html"}, r.Removed)
+	require.Contains(t, r.Written, "cheese.html")
+	require.False(t, exists(filepath.Join(out, "making", "curds.html")))
+}
+
+func TestBuild_3(t *testing.T) {
+	nb, out, cleanup := tempSite(t)
+	defer cleanup()
+
+	// Page links are escaped so '#' isn't read as the start of a fragment
+	writeFile(t, nb.Root(), "langs/c#.dw", "# C#\n. **Rennet** for code")
+	_, e := Build(nb, out)
+	require.Nil(t, e)
+
+	require.Contains(t, read(t, out, "index.html"), `<a href="langs/c%23.html">C#</a>`)
+	require.Contains(t, read(t, out, "key-phrases.html"), `<a href="langs/c%23.html#c">C# › C#</a>`)
+	require.Contains(t, read(t, out, "search.json"), `"page":"langs/c%23.html"`)
+}
+
+func TestBuild_4(t *testing.T) {
+	nb, out, cleanup := tempSite(t)
+	defer cleanup()
+
+	// Repeated topics are linked to by their own, unique, anchors
+	writeFile(t, nb.Root(), "wine.dw", "# Wine\n## Reds\n. Port\n## Reds\n. **Tannin**")
+	_, e := Build(nb, out)
+	require.Nil(t, e)
+
+	page := read(t, out, "wine.html")
+	require.Contains(t, page, `<a href="#reds-1">Reds</a>`)
+	require.Contains(t, page, `<h2 id="reds-1">Reds</h2>`)
+	require.Contains(t, read(t, out, "key-phrases.html"), `<a href="wine.html#reds-1">Wine › Reds</a>`)
+}
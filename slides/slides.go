@@ -0,0 +1,284 @@
+// Package slides renders parsed notes as a slide deck, a single self
+// contained HTML file that works offline.
+//
+// Each topic starts a slide and each sub topic starts a sub slide below it,
+// so a presenter can step down into the detail of a topic or skip across to
+// the next. Deeper topics become headings within the slide. Content before
+// the first topic becomes an untitled first slide. List items are revealed
+// one at a time as bullet builds.
+//
+// Keys:
+//   - Space, Page Down, Enter, or a click: next build or slide
+//   - Backspace or Page Up: previous build or slide
+//   - Right and Left: next and previous slide, skipping sub slides
+//   - Down and Up: next and previous sub slide
+//   - Home and End: first and last slide
+//   - N: show or hide speaker notes
+//   - F: full screen
+//
+// The position is kept in the URL fragment, e.g. '#3.2' for the second sub
+// slide of the third slide, so reloading stays on the same slide.
+package slides
+
+import (
+	gohtml "html"
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/block"
+	"github.com/PaulioRandall/daft-wullie-go/html"
+)
+
+// Options configures how notes are rendered.
+type Options struct {
+	// Notes turns text lines into speaker notes rather than showing them on
+	// the slides.
+	Notes bool
+}
+
+// Render renders 'notes' as a slide deck. The title of the deck is the first
+// topic within the notes.
+func Render(notes ast.Notes) string {
+	return RenderWith(notes, Options{})
+}
+
+// RenderWith renders 'notes', configured by 'opts', as a slide deck.
+func RenderWith(notes ast.Notes, opts Options) string {
+	sb := &strings.Builder{}
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	sb.WriteString(`<meta charset="utf-8">` + "\n")
+	sb.WriteString(`<meta name="viewport" content="width=device-width, initial-scale=1">` + "\n")
+	sb.WriteString("<title>" + gohtml.EscapeString(html.Title(notes)) + "</title>\n")
+	sb.WriteString("<style>\n" + html.Style + Style + "</style>\n")
+	sb.WriteString("</head>\n<body>\n")
+	sb.WriteString(`<main class="dw-deck">` + "\n")
+	writeDeck(sb, decks(block.Build(notes)), opts)
+	sb.WriteString("</main>\n")
+	sb.WriteString(`<div class="dw-notes-panel" hidden></div>` + "\n")
+	sb.WriteString(`<div class="dw-progress"></div>` + "\n")
+	sb.WriteString(script)
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+// slide is a heading and the blocks following it up to the next slide.
+type slide struct {
+	heading *block.Heading
+	blocks  []block.Block
+}
+
+// column is a slide followed by its sub slides.
+type column []*slide
+
+// decks groups blocks into columns of slides. Topics start columns and sub
+// topics start slides within them.
+func decks(bs []block.Block) []column {
+	cols := []column{}
+	var cur *slide
+
+	for _, b := range bs {
+		if h, ok := b.(block.Heading); ok && h.Level <= 2 {
+			cur = &slide{heading: &h}
+			if h.Level == 1 || len(cols) == 0 {
+				cols = append(cols, column{})
+			}
+			cols[len(cols)-1] = append(cols[len(cols)-1], cur)
+			continue
+		}
+
+		if _, ok := b.(block.Break); ok {
+			continue
+		}
+
+		if cur == nil {
+			cur = &slide{}
+			cols = append(cols, column{cur})
+		}
+		cur.blocks = append(cur.blocks, b)
+	}
+
+	return cols
+}
+
+func writeDeck(sb *strings.Builder, cols []column, opts Options) {
+	for i, col := range cols {
+		sb.WriteString(`<section class="dw-column">` + "\n")
+		for j, s := range col {
+			id := strconv.Itoa(i+1) + "." + strconv.Itoa(j+1)
+			sb.WriteString(`<section class="dw-slide" id="` + id + `">` + "\n")
+			writeSlide(sb, s, opts)
+			sb.WriteString("</section>\n")
+		}
+		sb.WriteString("</section>\n")
+	}
+}
+
+func writeSlide(sb *strings.Builder, s *slide, opts Options) {
+	if s.heading != nil {
+		tag := "h" + strconv.Itoa(s.heading.Level)
+		sb.WriteString("<" + tag + ">" + html.Inline(s.heading.Nodes) + "</" + tag + ">\n")
+	}
+
+	notes := &strings.Builder{}
+	for _, b := range s.blocks {
+		switch v := b.(type) {
+		case block.Heading:
+			tag := "h" + strconv.Itoa(headingLevel(v.Level))
+			sb.WriteString("<" + tag + ">" + html.Inline(v.Nodes) + "</" + tag + ">\n")
+
+		case block.Para:
+			if opts.Notes {
+				notes.WriteString("<p>" + html.Inline(v.Nodes) + "</p>\n")
+			} else {
+				sb.WriteString("<p>" + html.Inline(v.Nodes) + "</p>\n")
+			}
+
+		case *block.List:
+			writeList(sb, v)
+		}
+	}
+
+	if notes.Len() > 0 {
+		sb.WriteString(`<aside class="dw-notes">` + "\n" + notes.String() + "</aside>\n")
+	}
+}
+
+// writeList writes a list with each item as a build, revealed in turn.
+func writeList(sb *strings.Builder, l *block.List) {
+	tag := "ul"
+	if l.Ordered {
+		tag = "ol"
+	}
+
+	sb.WriteString("<" + tag)
+	if l.Ordered && len(l.Items) > 0 && l.Items[0].Num != 1 {
+		sb.WriteString(` start="` + strconv.Itoa(l.Items[0].Num) + `"`)
+	}
+	sb.WriteString(">\n")
+
+	for i, item := range l.Items {
+		sb.WriteString(`<li class="dw-build"`)
+		if l.Ordered && i > 0 && item.Start > 0 {
+			sb.WriteString(` value="` + strconv.Itoa(item.Num) + `"`)
+		}
+		sb.WriteString(">" + html.Inline(item.Nodes))
+		if len(item.Lists) > 0 {
+			sb.WriteString("\n")
+		}
+		for _, sub := range item.Lists {
+			writeList(sb, sub)
+		}
+		sb.WriteString("</li>\n")
+	}
+
+	sb.WriteString("</" + tag + ">\n")
+}
+
+func headingLevel(level int) int {
+	if level > 6 {
+		return 6
+	}
+	return level
+}
+
+// Style lays out the deck showing one slide at a time.
+const Style = `html, body { margin: 0; height: 100%; overflow: hidden; background: #fff; }
+body { font-family: sans-serif; font-size: 3.2vmin; color: #222; }
+.dw-slide { display: none; box-sizing: border-box; height: 100vh; padding: 6vmin 8vmin; overflow: auto; }
+.dw-slide.dw-current { display: block; }
+.dw-slide h1 { font-size: 2.2em; margin: 0 0 0.6em; }
+.dw-slide h2 { font-size: 1.7em; margin: 0 0 0.6em; }
+.dw-slide li { margin: 0.3em 0; }
+.dw-build { visibility: hidden; }
+.dw-build.dw-shown { visibility: visible; }
+.dw-notes { display: none; }
+.dw-notes-panel { position: fixed; left: 0; right: 0; bottom: 0; max-height: 30vh; overflow: auto; padding: 1em 2em;
+  background: #333; color: #eee; font-size: 0.7em; }
+.dw-progress { position: fixed; right: 1em; bottom: 0.5em; font-size: 0.6em; color: #888; }
+@media print {
+  html, body { overflow: visible; height: auto; }
+  .dw-slide { display: block; height: auto; page-break-after: always; }
+  .dw-build { visibility: visible; }
+  .dw-notes-panel, .dw-progress { display: none; }
+}
+`
+
+// script handles navigation, builds, and speaker notes.
+const script = `<script>
+(function() {
+  var cols = Array.prototype.map.call(document.querySelectorAll(".dw-column"), function(c) {
+    return c.querySelectorAll(".dw-slide");
+  });
+  var panel = document.querySelector(".dw-notes-panel");
+  var progress = document.querySelector(".dw-progress");
+  var c = 0, s = 0, b = 0;
+
+  function slide() { return cols.length ? cols[c][s] : null; }
+  function builds() { return slide() ? slide().querySelectorAll(".dw-build") : []; }
+
+  function show() {
+    var cur = slide();
+    Array.prototype.forEach.call(document.querySelectorAll(".dw-slide"), function(el) {
+      el.classList.toggle("dw-current", el === cur);
+    });
+    Array.prototype.forEach.call(builds(), function(el, i) {
+      el.classList.toggle("dw-shown", i < b);
+    });
+    var notes = cur ? cur.querySelector(".dw-notes") : null;
+    panel.innerHTML = notes ? notes.innerHTML : "";
+    progress.textContent = cols.length ? (c + 1) + "." + (s + 1) + " / " + cols.length : "";
+    if (cur) history.replaceState(null, "", "#" + cur.id);
+  }
+
+  function go(col, sub, build) {
+    if (col < 0 || col >= cols.length) return;
+    c = col;
+    s = Math.max(0, Math.min(sub, cols[c].length - 1));
+    b = build === "all" ? builds().length : build;
+    show();
+  }
+
+  function next() {
+    if (b < builds().length) { b++; show(); }
+    else if (s + 1 < cols[c].length) go(c, s + 1, 0);
+    else go(c + 1, 0, 0);
+  }
+
+  function prev() {
+    if (b > 0) { b--; show(); }
+    else if (s > 0) go(c, s - 1, "all");
+    else if (c > 0) go(c - 1, cols[c - 1].length - 1, "all");
+  }
+
+  document.addEventListener("keydown", function(e) {
+    if (e.altKey || e.ctrlKey || e.metaKey || !cols.length) return;
+    switch (e.key) {
+    case " ": case "PageDown": case "Enter": next(); break;
+    case "Backspace": case "PageUp": prev(); break;
+    case "ArrowRight": go(c + 1, 0, 0); break;
+    case "ArrowLeft": go(c - 1, 0, 0); break;
+    case "ArrowDown": go(c, s + 1, 0); break;
+    case "ArrowUp": go(c, s - 1, 0); break;
+    case "Home": go(0, 0, 0); break;
+    case "End": go(cols.length - 1, 0, 0); break;
+    case "n": case "N": panel.hidden = !panel.hidden; break;
+    case "f": case "F":
+      if (document.fullscreenElement) document.exitFullscreen();
+      else if (document.documentElement.requestFullscreen) document.documentElement.requestFullscreen();
+      break;
+    default: return;
+    }
+    e.preventDefault();
+  });
+
+  document.addEventListener("click", function(e) {
+    if (!e.target.closest("a") && cols.length) next();
+  });
+
+  var m = /^#(\d+)\.(\d+)$/.exec(location.hash);
+  if (m) go(+m[1] - 1, +m[2] - 1, 0);
+  else go(0, 0, 0);
+})();
+</script>
+`
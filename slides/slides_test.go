@@ -0,0 +1,89 @@
+package slides
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func deck(s string, opts Options) string {
+	out := RenderWith(parser.ParseAll(scanner.ScanAll(s)), opts)
+	start := strings.Index(out, `<main class="dw-deck">`)
+	end := strings.Index(out, "</main>")
+	return out[start+len(`<main class="dw-deck">`)+1 : end]
+}
+
+func TestRender_1(t *testing.T) {
+
+	in := `Intro line
+# Cheese
+Said with a smile
+## Types
+. **Chedder**
+.. +Mild+
+### Soft
+!3 Brie
+# Wine`
+
+	exp := `<section class="dw-column">
+<section class="dw-slide" id="1.1">
+<p>Intro line</p>
+</section>
+</section>
+<section class="dw-column">
+<section class="dw-slide" id="2.1">
+<h1>Cheese</h1>
+<p>Said with a smile</p>
+</section>
+<section class="dw-slide" id="2.2">
+<h2>Types</h2>
+<ul>
+<li class="dw-build"><mark class="dw-key-phrase">Chedder</mark>
+<ul>
+<li class="dw-build"><span class="dw-positive">Mild</span></li>
+</ul>
+</li>
+</ul>
+<h3>Soft</h3>
+<ol start="3">
+<li class="dw-build">Brie</li>
+</ol>
+</section>
+</section>
+<section class="dw-column">
+<section class="dw-slide" id="3.1">
+<h1>Wine</h1>
+</section>
+</section>
+`
+	require.Equal(t, exp, deck(in, Options{}))
+}
+
+func TestRender_2(t *testing.T) {
+	in := "# Cheese\nSaid with a smile\n. Chedder"
+
+	exp := `<section class="dw-column">
+<section class="dw-slide" id="1.1">
+<h1>Cheese</h1>
+<ul>
+<li class="dw-build">Chedder</li>
+</ul>
+<aside class="dw-notes">
+<p>Said with a smile</p>
+</aside>
+</section>
+</section>
+`
+	require.Equal(t, exp, deck(in, Options{Notes: true}))
+
+	out := Render(parser.ParseAll(scanner.ScanAll(in)))
+	require.Contains(t, out, "<title>Cheese</title>")
+	require.Contains(t, out, ".dw-key-phrase")
+	require.Contains(t, out, "<script>")
+	require.NotContains(t, out, "src=")
+	require.NotContains(t, out, "href=\"http")
+}
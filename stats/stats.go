@@ -0,0 +1,176 @@
+// Package stats counts the nodes, words, and characters of notes, per note
+// and per topic, and aggregates them across a notebook.
+package stats
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+// WordsPerMinute is the reading speed used to estimate reading times.
+const WordsPerMinute = 200
+
+// Annotations are the node types used to annotate text, i.e. those whose
+// density is reported.
+var Annotations = []ast.NodeType{
+	ast.KeyPhrase,
+	ast.Positive,
+	ast.Negative,
+	ast.Strong,
+	ast.Quote,
+	ast.Artifact,
+	ast.Snippet,
+	ast.Link,
+	ast.WikiLink,
+	ast.Tag,
+}
+
+type (
+	// Stats are the statistics of some notes.
+	Stats struct {
+		Lines  int                  `json:"lines"`
+		Words  int                  `json:"words"`
+		Chars  int                  `json:"chars"`
+		Counts map[ast.NodeType]int `json:"counts"`
+
+		// ReadingMinutes is the estimated time to read the notes, see
+		// WordsPerMinute.
+		ReadingMinutes float64 `json:"readingMinutes"`
+
+		// Density is the number of each annotation per 100 words.
+		Density map[ast.NodeType]float64 `json:"density"`
+	}
+
+	// Topic is the statistics of the lines under a topic, up to the next
+	// topic of any level.
+	Topic struct {
+		Title string `json:"title"`
+		Level int    `json:"level"`
+		Line  int    `json:"line"`
+		Stats
+	}
+
+	// Note is the statistics of a note and each of its topics.
+	Note struct {
+		Path string `json:"path"`
+		Stats
+		Topics []Topic `json:"topics"`
+	}
+
+	// Notebook is the statistics of each note in a notebook and their total.
+	Notebook struct {
+		Notes []Note `json:"notes"`
+		Total Stats  `json:"total"`
+	}
+)
+
+// Of returns the statistics of 'notes'.
+func Of(notes ast.Notes) Stats {
+	s := Stats{Counts: map[ast.NodeType]int{}}
+	ast.DescendNotes(notes, func(n ast.Node, _, _, _ int) {
+		s.Counts[n.Type()]++
+	})
+
+	for _, line := range notes {
+		text := line.Text()
+		s.Words += len(strings.Fields(text))
+		s.Chars += utf8.RuneCountInString(text)
+	}
+
+	s.Lines = len(notes)
+	return s.derive()
+}
+
+// OfNote returns the statistics of the note at 'path' and of each of its
+// topics. Lines before the first topic are not part of any topic.
+func OfNote(path string, notes ast.Notes) Note {
+	r := Note{
+		Path:   path,
+		Stats:  Of(notes),
+		Topics: []Topic{},
+	}
+
+	start := -1
+	add := func(end int) {
+		if start < 0 {
+			return
+		}
+		h := notes[start]
+		r.Topics = append(r.Topics, Topic{
+			Title: strings.TrimSpace(h.Text()),
+			Level: ast.Level(h),
+			Line:  start + 1,
+			Stats: Of(notes[start+1 : end]),
+		})
+	}
+
+	for i, n := range notes {
+		if n.Type() == ast.Topic || n.Type() == ast.SubTopic {
+			add(i)
+			start = i
+		}
+	}
+	add(len(notes))
+
+	return r
+}
+
+// OfNotebook returns the statistics of each note and their total.
+func OfNotebook(notes []Note) Notebook {
+	r := Notebook{Notes: notes}
+	stats := make([]Stats, len(notes))
+	for i, n := range notes {
+		stats[i] = n.Stats
+	}
+	r.Total = Sum(stats...)
+	return r
+}
+
+// Sum returns the total of 'stats'.
+func Sum(stats ...Stats) Stats {
+	r := Stats{Counts: map[ast.NodeType]int{}}
+	for _, s := range stats {
+		r.Lines += s.Lines
+		r.Words += s.Words
+		r.Chars += s.Chars
+		for k, v := range s.Counts {
+			r.Counts[k] += v
+		}
+	}
+	return r.derive()
+}
+
+// Annotations returns the total number of annotations, see Annotations.
+func (s Stats) Annotations() int {
+	n := 0
+	for _, t := range Annotations {
+		n += s.Counts[t]
+	}
+	return n
+}
+
+// Topics returns the number of topics and sub topics.
+func (s Stats) Topics() int {
+	return s.Counts[ast.Topic] + s.Counts[ast.SubTopic]
+}
+
+// derive calculates the statistics derived from the counts.
+func (s Stats) derive() Stats {
+	s.ReadingMinutes = round(float64(s.Words) / WordsPerMinute)
+	s.Density = make(map[ast.NodeType]float64, len(Annotations))
+	for _, t := range Annotations {
+		s.Density[t] = 0
+		if s.Words > 0 {
+			s.Density[t] = round(float64(s.Counts[t]) * 100 / float64(s.Words))
+		}
+	}
+	return s
+}
+
+// round rounds 'f' to two decimal places.
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
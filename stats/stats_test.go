@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+)
+
+const note = `Intro text
+# Cheese
+. **Rennet** is +cheap+
+## Curds
+-Squeaky- **curds** and **whey**`
+
+func TestOf_1(t *testing.T) {
+	s := Of(notebook.Parse(note))
+
+	require.Equal(t, 5, s.Lines)
+	require.Equal(t, 11, s.Words)
+	require.Equal(t, 1, s.Counts[ast.Topic])
+	require.Equal(t, 1, s.Counts[ast.SubTopic])
+	require.Equal(t, 2, s.Topics())
+	require.Equal(t, 3, s.Counts[ast.KeyPhrase])
+	require.Equal(t, 5, s.Annotations())
+	require.Equal(t, 27.27, s.Density[ast.KeyPhrase])
+	require.Equal(t, 0.06, s.ReadingMinutes)
+}
+
+func TestOfNote_1(t *testing.T) {
+	n := OfNote("cheese.dw", notebook.Parse(note))
+
+	require.Equal(t, 2, len(n.Topics))
+	require.Equal(t, "Cheese", n.Topics[0].Title)
+	require.Equal(t, 2, n.Topics[0].Line)
+	require.Equal(t, 1, n.Topics[0].Counts[ast.KeyPhrase])
+	require.Equal(t, 2, n.Topics[1].Level)
+	require.Equal(t, 2, n.Topics[1].Counts[ast.KeyPhrase])
+	require.Equal(t, 1, n.Topics[1].Counts[ast.Negative])
+}
+
+func TestOfNotebook_1(t *testing.T) {
+	a := OfNote("a.dw", notebook.Parse(note))
+	b := OfNote("b.dw", notebook.Parse("+good+ words"))
+	nb := OfNotebook([]Note{a, b})
+
+	require.Equal(t, 13, nb.Total.Words)
+	require.Equal(t, 2, nb.Total.Counts[ast.Positive])
+
+	js, e := json.Marshal(nb)
+	require.Nil(t, e)
+	require.Contains(t, string(js), `"path":"b.dw","lines":1,"words":2`)
+}
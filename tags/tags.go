@@ -0,0 +1,142 @@
+// Package tags indexes the inline tags, e.g. '#idea', used within notes.
+package tags
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+type (
+	// File is a parsed note along with its path.
+	File struct {
+		Path  string
+		Notes ast.Notes
+	}
+
+	// Location is the line of a note upon which a tag appears.
+	Location struct {
+		Path string
+		Line int
+	}
+
+	// Tag is a tag along with every location it appears.
+	Tag struct {
+		Name      string
+		Locations []Location
+	}
+)
+
+// Count returns the number of times the tag appears.
+func (t Tag) Count() int {
+	return len(t.Locations)
+}
+
+// Normalise returns the canonical form of the tag 'name' so tags are
+// matched regardless of case or a leading '#'.
+func Normalise(name string) string {
+	return strings.ToLower(strings.TrimPrefix(name, "#"))
+}
+
+// Lines returns the normalised names of the tags on each line of 'notes'. The
+// key is the line number, starting from one, and tags are listed in the order
+// they appear.
+func Lines(notes ast.Notes) map[int][]string {
+	r := map[int][]string{}
+	for i, n := range notes {
+		ast.DecendNode(n, func(n ast.Node, _, _, _ int) {
+			if n.Type() == ast.Tag {
+				r[i+1] = append(r[i+1], Normalise(n.Text()))
+			}
+		})
+	}
+	return r
+}
+
+// Index returns every tag used within 'files' sorted by name. Locations are
+// listed in the order of the files then by line.
+func Index(files []File) []Tag {
+
+	byName := map[string]*Tag{}
+
+	for _, f := range files {
+		lines := Lines(f.Notes)
+		for _, line := range sortedLines(lines) {
+			for _, name := range lines[line] {
+				t, ok := byName[name]
+				if !ok {
+					t = &Tag{Name: name}
+					byName[name] = t
+				}
+				t.Locations = append(t.Locations, Location{Path: f.Path, Line: line})
+			}
+		}
+	}
+
+	r := make([]Tag, 0, len(byName))
+	for _, t := range byName {
+		r = append(r, *t)
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Name < r[j].Name
+	})
+	return r
+}
+
+// Filter returns the line numbers of 'notes' that carry the tag 'name' along
+// with the headings those lines fall under, so the filtered lines still read
+// well. Line numbers start from one.
+func Filter(notes ast.Notes, name string) []int {
+
+	name = Normalise(name)
+	lines := Lines(notes)
+
+	r := []int{}
+	headings := []int{} // Headings not yet included, shallowest first
+
+	for i, n := range notes {
+		line := i + 1
+
+		if n.Type() == ast.Topic || n.Type() == ast.SubTopic {
+			level := ast.Level(n)
+			for len(headings) > 0 && ast.Level(notes[headings[len(headings)-1]-1]) >= level {
+				headings = headings[:len(headings)-1]
+			}
+			headings = append(headings, line)
+		}
+
+		if !contains(lines[line], name) {
+			continue
+		}
+
+		for _, h := range headings {
+			if h != line {
+				r = append(r, h)
+			}
+		}
+		headings = headings[:0]
+		r = append(r, line)
+	}
+
+	return r
+}
+
+func sortedLines(lines map[int][]string) []int {
+	r := make([]int, 0, len(lines))
+	for line := range lines {
+		r = append(r, line)
+	}
+	sort.Ints(r)
+	return r
+}
+
+func contains(names []string, name string) bool {
+	for _, v := range names {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
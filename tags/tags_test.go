@@ -0,0 +1,67 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parse(s string) ast.Notes {
+	return parser.ParseAll(scanner.ScanAll(s))
+}
+
+func TestIndex_1(t *testing.T) {
+
+	a := parse(`# Cheese
+Some #idea here, +and #todo+
+. Brie #IDEA`)
+
+	b := parse(`Nothing #todo`)
+
+	exp := []Tag{
+		Tag{Name: "idea", Locations: []Location{{"a.dw", 2}, {"a.dw", 3}}},
+		Tag{Name: "todo", Locations: []Location{{"a.dw", 2}, {"b.dw", 1}}},
+	}
+
+	act := Index([]File{{"a.dw", a}, {"b.dw", b}})
+	require.Equal(t, exp, act)
+	require.Equal(t, 2, act[0].Count())
+}
+
+func TestFilter_1(t *testing.T) {
+
+	in := parse(`# Cheese
+Untagged
+## Types
+. Brie #todo
+. Stilton
+## History
+### Early
+Who knows
+### Late
+Ask #todo
+Again #Todo`)
+
+	exp := []int{1, 3, 4, 6, 9, 10, 11}
+
+	act := Filter(in, "#todo")
+	require.Equal(t, exp, act)
+}
+
+func TestIndex_2(t *testing.T) {
+
+	// Trailing dashes and slashes are not part of a tag nor open a phrase
+	a := parse(". Ask #follow-up/cheese- and #todo/ later")
+
+	exp := []Tag{
+		Tag{Name: "follow-up/cheese", Locations: []Location{{"a.dw", 1}}},
+		Tag{Name: "todo", Locations: []Location{{"a.dw", 1}}},
+	}
+
+	require.Equal(t, exp, Index([]File{{"a.dw", a}}))
+	require.Equal(t, " Ask follow-up/cheese- and todo/ later", a[0].Text())
+}
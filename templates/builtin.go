@@ -0,0 +1,69 @@
+package templates
+
+// builtin holds the built in templates by name.
+var builtin = map[string]string{
+	"journal": `# {{date}}
+
+## Notes
+
+## Actions
+`,
+
+	"meeting": `# {{title}}
+${{date}}$
+
+## Attendees
+. ${{attendees...}}$
+
+## Agenda
+.
+
+## Notes
+
+## Actions
+!
+`,
+
+	"lecture": `# {{title}}
+${{date}}$ ${{lecturer}}$
+
+## Key Points
+.
+
+## Notes
+
+## Questions
+.
+`,
+
+	"interview": `# {{title}}
+${{date}}$
+
+## Participants
+. Interviewer ${{interviewer}}$
+. Interviewee ${{interviewee}}$
+
+## Background
+
+## Answers
+
+## Observations
+.
+`,
+
+	"research": `# {{title}}
+${{date}}$
+
+## Goal
+{{goal}}
+
+## Participants
+. ${{participants...}}$
+
+## Findings
+.
+
+## Next Steps
+!
+`,
+}
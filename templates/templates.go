@@ -0,0 +1,221 @@
+// Package templates creates new notes from templates. A template is a Daft
+// Wullie note containing placeholders, e.g. '{{title}}', which are replaced
+// with values when the note is created.
+//
+// A placeholder name ending in '...', e.g. '{{attendees...}}', is a list
+// placeholder. Its value is split on commas and the line containing it is
+// repeated once per item, so a template line such as '. ${{attendees...}}$'
+// becomes a bullet point per attendee. A line may only contain one list
+// placeholder.
+package templates
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+type (
+	// Template is a named note template.
+	Template struct {
+		Name string
+		Text string
+	}
+
+	// Placeholder is a placeholder within a template.
+	Placeholder struct {
+		Name string
+		List bool // True if the line containing it is repeated per item
+		Line int  // Line of its first use, starting from one
+	}
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)((?:\.\.\.)?)\s*\}\}`)
+
+// Builtin returns the built in templates sorted by name.
+func Builtin() []Template {
+	r := make([]Template, 0, len(builtin))
+	for name, text := range builtin {
+		r = append(r, Template{Name: name, Text: text})
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Name < r[j].Name
+	})
+	return r
+}
+
+// Lookup returns the built in template called 'name'.
+func Lookup(name string) (Template, bool) {
+	text, ok := builtin[strings.ToLower(name)]
+	return Template{Name: name, Text: text}, ok
+}
+
+// Load reads the template file 'file'. The name of the template is the file
+// name without its extension.
+func Load(file string) (Template, error) {
+	b, e := ioutil.ReadFile(file)
+	if e != nil {
+		return Template{}, e
+	}
+
+	base := filepath.Base(file)
+	return Template{
+		Name: strings.TrimSuffix(base, filepath.Ext(base)),
+		Text: string(b),
+	}, nil
+}
+
+// Placeholders returns the placeholders used within the template in order of
+// first use. Each name is only listed once.
+func (t Template) Placeholders() []Placeholder {
+	r := []Placeholder{}
+	seen := map[string]bool{}
+
+	for i, line := range splitLines(t.Text) {
+		for _, m := range placeholderPattern.FindAllStringSubmatch(line, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				r = append(r, Placeholder{Name: m[1], List: m[2] == "...", Line: i + 1})
+			}
+		}
+	}
+	return r
+}
+
+// Malformed returns true if the line 's' contains the start of a placeholder,
+// i.e. '{{', that is not part of a valid placeholder.
+func Malformed(s string) bool {
+	s = placeholderPattern.ReplaceAllString(s, "")
+	return strings.Contains(s, "{{")
+}
+
+// Contains returns true if the line 's' contains a placeholder.
+func Contains(s string) bool {
+	return placeholderPattern.MatchString(s)
+}
+
+// Fill returns the template text with its placeholders replaced by 'values',
+// keyed by placeholder name. Values are escaped so they are read as text,
+// except within topics which are always read as text. An error is returned if
+// any placeholder has no value.
+func (t Template) Fill(values map[string]string) (string, error) {
+	missing := []string{}
+	for _, p := range t.Placeholders() {
+		if _, ok := values[p.Name]; !ok {
+			missing = append(missing, p.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("no value for %s", strings.Join(missing, ", "))
+	}
+
+	r := []string{}
+	for i, line := range splitLines(t.Text) {
+		lines, e := fillLine(line, values)
+		if e != nil {
+			return "", fmt.Errorf("line %d: %v", i+1, e)
+		}
+		r = append(r, lines...)
+	}
+	return strings.Join(r, "\n"), nil
+}
+
+// fillLine fills the placeholders of a single template line. The result is
+// more than one line if the line contains a list placeholder, and no lines
+// if the list is empty.
+func fillLine(line string, values map[string]string) ([]string, error) {
+	list := ""
+	for _, m := range placeholderPattern.FindAllStringSubmatch(line, -1) {
+		if m[2] != "..." {
+			continue
+		}
+		if list != "" && list != m[1] {
+			return nil, fmt.Errorf("more than one list placeholder")
+		}
+		list = m[1]
+	}
+
+	if list == "" {
+		return []string{replace(line, values)}, nil
+	}
+
+	r := []string{}
+	for _, item := range Split(values[list]) {
+		v := copyValues(values)
+		v[list] = item
+		r = append(r, replace(line, v))
+	}
+	return r, nil
+}
+
+// Split splits the value of a list placeholder into its items, i.e. the
+// non-empty comma separated values.
+func Split(s string) []string {
+	r := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			r = append(r, item)
+		}
+	}
+	return r
+}
+
+func replace(line string, values map[string]string) string {
+	topic := strings.HasPrefix(strings.TrimSpace(line), "#")
+	sb := strings.Builder{}
+	prev := 0
+
+	for _, loc := range placeholderPattern.FindAllStringSubmatchIndex(line, -1) {
+		before := line[prev:loc[0]]
+		sb.WriteString(before)
+		prev = loc[1]
+
+		v := strings.Join(strings.Fields(values[line[loc[2]:loc[3]]]), " ")
+		if topic || v == "" {
+			sb.WriteString(v)
+			continue
+		}
+
+		v = ast.EscapeText(v)
+		if lead := lineLead(line[:loc[0]]); strings.ContainsRune(lead, []rune(v)[0]) {
+			v = `\` + v // Would otherwise be read as part of the line symbol
+		}
+		sb.WriteString(v)
+	}
+
+	sb.WriteString(line[prev:])
+	return sb.String()
+}
+
+// lineLead returns the runes that must be escaped at the start of a value
+// placed directly after 'prefix', i.e. those that would be read as part of a
+// line symbol.
+func lineLead(prefix string) string {
+	s := strings.TrimLeft(prefix, " \t")
+	switch {
+	case s == "":
+		return "#.!"
+	case strings.Trim(s, ".") == "":
+		return "."
+	case strings.Trim(s, "!") == "":
+		return "!0123456789"
+	}
+	return ""
+}
+
+func copyValues(values map[string]string) map[string]string {
+	r := make(map[string]string, len(values))
+	for k, v := range values {
+		r[k] = v
+	}
+	return r
+}
+
+func splitLines(s string) []string {
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,69 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/PaulioRandall/daft-wullie-go/lint"
+	"github.com/PaulioRandall/daft-wullie-go/notebook"
+	"github.com/PaulioRandall/daft-wullie-go/templates"
+)
+
+func TestPlaceholders_1(t *testing.T) {
+	tm := templates.Template{Text: "# {{title}}\n. ${{ people... }}$ {{title}}"}
+	exp := []templates.Placeholder{
+		{Name: "title", Line: 1},
+		{Name: "people", List: true, Line: 2},
+	}
+	require.Equal(t, exp, tm.Placeholders())
+}
+
+func TestFill_1(t *testing.T) {
+	tm := templates.Template{Text: "# {{title}}\n. ${{people...}}$\n{{note}}\n.{{note}}\n!{{n}}"}
+	s, e := tm.Fill(map[string]string{
+		"title":  "Cheese-making 101",
+		"people": "Alice, Bob,",
+		"note":   ".well-known",
+		"n":      "5 apples",
+	})
+
+	require.Nil(t, e)
+	exp := "# Cheese-making 101\n. $Alice$\n. $Bob$\n\\.well\\-known\n.\\.well\\-known\n!\\5 apples"
+	require.Equal(t, exp, s)
+}
+
+func TestFill_2(t *testing.T) {
+	_, e := templates.Template{Text: "{{a}} {{b}}"}.Fill(map[string]string{"b": ""})
+	require.EqualError(t, e, "no value for a")
+
+	_, e = templates.Template{Text: "{{a...}} {{b...}}"}.Fill(map[string]string{"a": "", "b": ""})
+	require.EqualError(t, e, "line 1: more than one list placeholder")
+}
+
+func TestBuiltin_1(t *testing.T) {
+	values := map[string]string{
+		"title":        "Weekly *sync* - cheese",
+		"date":         "2021-02-06",
+		"attendees":    "Alice, Bob-Jones",
+		"lecturer":     "Dr \"Curd\"",
+		"interviewer":  "Alice",
+		"interviewee":  "Bob",
+		"goal":         "#1 +find+ a $cheap$ rennet",
+		"participants": "Alice",
+	}
+
+	for _, tm := range templates.Builtin() {
+		require.Empty(t, lint.Check(tm.Text, lint.Options{Template: true}), tm.Name)
+
+		s, e := tm.Fill(values)
+		require.Nil(t, e, tm.Name)
+		require.Empty(t, lint.Check(s, lint.Options{}), tm.Name)
+
+		if tm.Name == "journal" {
+			continue // Dated by its file name
+		}
+		n := notebook.Parse(s)
+		require.Equal(t, "2021-02-06", notebook.MetaOf("x.dw", n).Date.Format("2006-01-02"), tm.Name)
+	}
+}
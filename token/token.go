@@ -27,6 +27,9 @@ const (
 	Snippet           = "Snippet"
 	Quote             = "Quote"
 	Artifact          = "Artifact"
+	Link              = "Link"
+	Tag               = "Tag"
+	WikiLink          = "WikiLink"
 	Escape            = "Escape"
 )
 
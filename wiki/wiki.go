@@ -0,0 +1,193 @@
+// Package wiki resolves wiki links, e.g. '[[Other Note#Topic]]', between the
+// notes of a notebook, reports broken links, and computes backlinks.
+//
+// A wiki link names a note by its file name, without the extension, or by its
+// title, i.e. its first topic. Names and topics are matched by their slugs,
+// see ast.Slug, so case and spacing do not matter. File names take priority
+// over titles. A link with no note name, e.g. '[[#Topic]]', links to a topic
+// within the same note.
+package wiki
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+)
+
+type (
+	// Note is a parsed note along with its path.
+	Note struct {
+		Path  string
+		Notes ast.Notes
+	}
+
+	// Link is a wiki link within a note along with where it resolves to.
+	Link struct {
+		From    string // Path of the note containing the link
+		Line    int    // Line of the link, starting from one
+		Target  ast.WikiLinkNode
+		To      string // Path of the linked note, empty if not found
+		Anchor  string // Slug of the linked topic, empty if none
+		Problem string // Why the link is broken, empty if it isn't
+	}
+
+	// Resolver resolves wiki links between a set of notes.
+	Resolver struct {
+		notes   []Note
+		byFile  map[string][]int // Note indexes by file name slug
+		byTitle map[string][]int // Note indexes by title slug
+		anchors []map[string]bool
+	}
+)
+
+// Broken returns true if the link could not be resolved.
+func (l Link) Broken() bool {
+	return l.Problem != ""
+}
+
+// NewResolver creates a Resolver for the wiki links between 'notes'.
+func NewResolver(notes []Note) *Resolver {
+	r := &Resolver{
+		notes:   notes,
+		byFile:  map[string][]int{},
+		byTitle: map[string][]int{},
+		anchors: make([]map[string]bool, len(notes)),
+	}
+
+	for i, n := range notes {
+		name := ast.Slug(Name(n.Path))
+		r.byFile[name] = append(r.byFile[name], i)
+
+		if title := ast.Slug(Title(n.Notes)); title != "" {
+			r.byTitle[title] = append(r.byTitle[title], i)
+		}
+
+		r.anchors[i] = map[string]bool{}
+		for _, line := range n.Notes {
+			if line.Type() == ast.Topic || line.Type() == ast.SubTopic {
+				r.anchors[i][ast.Slug(line.Text())] = true
+			}
+		}
+	}
+
+	return r
+}
+
+// Name returns the name of the note at 'path', i.e. its file name without
+// the extension.
+func Name(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// Title returns the text of the first topic within 'notes' or an empty string
+// if there isn't one.
+func Title(notes ast.Notes) string {
+	for _, n := range notes {
+		if n.Type() == ast.Topic {
+			return strings.TrimSpace(n.Text())
+		}
+	}
+	return ""
+}
+
+// Resolve resolves the wiki link 'l' found within the note at 'from'.
+func (r *Resolver) Resolve(from string, l ast.WikiLinkNode) Link {
+	link := Link{From: from, Target: l}
+
+	i, problem := r.find(from, l.Note)
+	if problem != "" {
+		link.Problem = problem
+		return link
+	}
+
+	link.To = r.notes[i].Path
+	if l.Topic == "" {
+		return link
+	}
+
+	anchor := ast.Slug(l.Topic)
+	if !r.anchors[i][anchor] {
+		link.Problem = "no topic '" + l.Topic + "' in " + link.To
+		return link
+	}
+
+	link.Anchor = anchor
+	return link
+}
+
+func (r *Resolver) find(from, name string) (int, string) {
+	if strings.TrimSpace(name) == "" {
+		for i, n := range r.notes {
+			if n.Path == from {
+				return i, ""
+			}
+		}
+		return 0, "no note at " + from
+	}
+
+	slug := ast.Slug(name)
+	found := r.byFile[slug]
+	if len(found) == 0 {
+		found = r.byTitle[slug]
+	}
+
+	switch len(found) {
+	case 0:
+		return 0, "no note named '" + name + "'"
+	case 1:
+		return found[0], ""
+	default:
+		return 0, "note name '" + name + "' is ambiguous"
+	}
+}
+
+// Links returns every wiki link within the notes, resolved, in order of note
+// then line.
+func (r *Resolver) Links() []Link {
+	links := []Link{}
+	for _, n := range r.notes {
+		for i, line := range n.Notes {
+			ast.DecendNode(line, func(node ast.Node, _, _, _ int) {
+				if l, ok := node.(ast.WikiLinkNode); ok {
+					link := r.Resolve(n.Path, l)
+					link.Line = i + 1
+					links = append(links, link)
+				}
+			})
+		}
+	}
+	return links
+}
+
+// Broken returns every wiki link within the notes that could not be
+// resolved.
+func (r *Resolver) Broken() []Link {
+	broken := []Link{}
+	for _, l := range r.Links() {
+		if l.Broken() {
+			broken = append(broken, l)
+		}
+	}
+	return broken
+}
+
+// Backlinks returns the resolved wiki links pointing to each note, keyed by
+// the path of the linked note. Links from a note to itself are not included.
+func (r *Resolver) Backlinks() map[string][]Link {
+	back := map[string][]Link{}
+	for _, l := range r.Links() {
+		if !l.Broken() && l.To != l.From {
+			back[l.To] = append(back[l.To], l)
+		}
+	}
+
+	for _, links := range back {
+		sort.SliceStable(links, func(i, j int) bool {
+			return links[i].From < links[j].From
+		})
+	}
+	return back
+}
@@ -0,0 +1,64 @@
+package wiki
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/daft-wullie-go/ast"
+	"github.com/PaulioRandall/daft-wullie-go/parser"
+	"github.com/PaulioRandall/daft-wullie-go/scanner"
+
+	"github.com/stretchr/testify/require"
+)
+
+func note(path, s string) Note {
+	return Note{Path: path, Notes: parser.ParseAll(scanner.ScanAll(s))}
+}
+
+func TestResolve_1(t *testing.T) {
+
+	r := NewResolver([]Note{
+		note("notes/cheese.dw", "# Cheese\n## Early History\nSee [[#early history]]"),
+		note("notes/brie_de_meaux.dw", "# Brie\nSee [[Cheese#Early-History]]"),
+		note("notes/other.dw", "[[brie de meaux]] [[brie]] [[cheese#Late]] [[Nope]]"),
+	})
+
+	link := func(from string, line int, note, topic, to, anchor, problem string) Link {
+		return Link{
+			From:    from,
+			Line:    line,
+			Target:  ast.MakeWikiLink(note, topic),
+			To:      to,
+			Anchor:  anchor,
+			Problem: problem,
+		}
+	}
+
+	exp := []Link{
+		link("notes/cheese.dw", 3, "", "early history", "notes/cheese.dw", "early-history", ""),
+		link("notes/brie_de_meaux.dw", 2, "Cheese", "Early-History", "notes/cheese.dw", "early-history", ""),
+		link("notes/other.dw", 1, "brie de meaux", "", "notes/brie_de_meaux.dw", "", ""),
+		link("notes/other.dw", 1, "brie", "", "notes/brie_de_meaux.dw", "", ""),
+		link("notes/other.dw", 1, "cheese", "Late", "notes/cheese.dw", "", "no topic 'Late' in notes/cheese.dw"),
+		link("notes/other.dw", 1, "Nope", "", "", "", "no note named 'Nope'"),
+	}
+
+	require.Equal(t, exp, r.Links())
+	require.Equal(t, exp[4:], r.Broken())
+
+	back := r.Backlinks()
+	require.Equal(t, []Link{exp[1]}, back["notes/cheese.dw"])
+	require.Equal(t, exp[2:4], back["notes/brie_de_meaux.dw"])
+	require.Empty(t, back["notes/other.dw"])
+}
+
+func TestResolve_2(t *testing.T) {
+
+	r := NewResolver([]Note{
+		note("a/cheese.dw", "# Cheese"),
+		note("b/cheese.dw", "# Cheese"),
+	})
+
+	l := r.Resolve("a/cheese.dw", ast.MakeWikiLink("Cheese", ""))
+	require.True(t, l.Broken())
+	require.Equal(t, "note name 'Cheese' is ambiguous", l.Problem)
+}